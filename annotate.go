@@ -0,0 +1,107 @@
+package dgexcel
+
+import (
+	"errors"
+	dgctx "github.com/darwinOrg/go-common/context"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const commentAuthor = "dgexcel"
+
+// AnnotateImportErrors 在原始上传文件的副本上标注导入返回的 *ImportError：错误单元格标红并添加批注，
+// 末尾追加“错误信息”列汇总每一行的错误，列标题写在导入时使用的表头行，无表头时不写
+func AnnotateImportErrors(ctx *dgctx.DgContext, reader io.Reader, importErr error) (*excelize.File, error) {
+	var ie *ImportError
+	if !errors.As(importErr, &ie) || ie == nil {
+		return nil, errors.New("import error required")
+	}
+	xlsx, err := openExcelReader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	//按工作表、行分组，未指定工作表时为第一个工作表
+	sheetErrors := make(map[string]map[int][]*CellError)
+	var sheetNames []string
+	for _, ce := range ie.CellErrors {
+		sheetName := ce.Sheet
+		if sheetName == "" {
+			sheetName = xlsx.GetSheetName(0)
+		}
+		if _, ok := sheetErrors[sheetName]; !ok {
+			sheetErrors[sheetName] = make(map[int][]*CellError)
+			sheetNames = append(sheetNames, sheetName)
+		}
+		sheetErrors[sheetName][ce.Row] = append(sheetErrors[sheetName][ce.Row], ce)
+	}
+
+	catalog := newMessageCatalog(GetLocale(ctx), nil)
+	styles := make(map[int]int)
+	wrapStyleId, _ := xlsx.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{WrapText: true, Vertical: "center"}})
+	for _, sheetName := range sheetNames {
+		rows, err := xlsx.GetRows(sheetName)
+		if err != nil {
+			return nil, err
+		}
+		maxCol := 0
+		for _, row := range rows {
+			maxCol = max(maxCol, len(row))
+		}
+		errorCol := ColumnIndexToName(maxCol)
+		_ = xlsx.SetColWidth(sheetName, errorCol, errorCol, 40)
+		if headerRow := ie.HeaderRow(sheetName); headerRow > 0 {
+			_ = xlsx.SetCellValue(sheetName, errorCol+strconv.Itoa(headerRow), catalog.render(MsgErrorColumn, nil))
+		}
+
+		for rowNum, rowErrors := range sheetErrors[sheetName] {
+			var rowMsgs []string
+			cellMsgs := make(map[string][]string)
+			var cells []string
+			for _, ce := range rowErrors {
+				rowMsgs = append(rowMsgs, ce.Message)
+				if ce.Column == "" {
+					continue
+				}
+				cell := ce.Column + strconv.Itoa(rowNum)
+				if _, ok := cellMsgs[cell]; !ok {
+					cells = append(cells, cell)
+				}
+				cellMsgs[cell] = append(cellMsgs[cell], ce.Message)
+			}
+			for _, cell := range cells {
+				markErrorCell(xlsx, sheetName, cell, strings.Join(cellMsgs[cell], "\n"), styles)
+			}
+			cell := errorCol + strconv.Itoa(rowNum)
+			_ = xlsx.SetCellValue(sheetName, cell, strings.Join(rowMsgs, "\n"))
+			_ = xlsx.SetCellStyle(sheetName, cell, cell, wrapStyleId)
+		}
+	}
+
+	return xlsx, nil
+}
+
+// markErrorCell 保留单元格原有样式并填充红色背景，同一原样式只创建一次新样式
+func markErrorCell(xlsx *excelize.File, sheetName, cell, message string, styles map[int]int) {
+	styleId, _ := xlsx.GetCellStyle(sheetName, cell)
+	errorStyleId, ok := styles[styleId]
+	if !ok {
+		style, err := xlsx.GetStyle(styleId)
+		if err != nil || style == nil {
+			style = &excelize.Style{}
+		}
+		style.Fill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}}
+		if errorStyleId, err = xlsx.NewStyle(style); err != nil {
+			return
+		}
+		styles[styleId] = errorStyleId
+	}
+	_ = xlsx.SetCellStyle(sheetName, cell, cell, errorStyleId)
+	_ = xlsx.AddComment(sheetName, excelize.Comment{
+		Author:    commentAuthor,
+		Cell:      cell,
+		Paragraph: []excelize.RichTextRun{{Text: message}},
+	})
+}
@@ -0,0 +1,46 @@
+package dgexcel
+
+import (
+	"bytes"
+	"errors"
+	dgctx "github.com/darwinOrg/go-common/context"
+	"testing"
+)
+
+func TestAnnotateImportErrors(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t,
+		[]any{"姓名", "手机号", "备注"},
+		[]any{"张三", "13800000000", "无"},
+		[]any{"", "13800000001", "无"},
+	)
+
+	_, err := BindExcelBytes2Struct[Applicant](ctx, data, 1, 2)
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	annotated, err := AnnotateImportErrors(ctx, bytes.NewReader(data), err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title, _ := annotated.GetCellValue(DefaultSheetName, "D1"); title != "错误信息" {
+		t.Fatalf("unexpected title: %s", title)
+	}
+	if summary, _ := annotated.GetCellValue(DefaultSheetName, "D3"); summary != importErr.CellErrors[0].Message {
+		t.Fatalf("unexpected summary: %s", summary)
+	}
+	if summary, _ := annotated.GetCellValue(DefaultSheetName, "D2"); summary != "" {
+		t.Fatalf("unexpected summary: %s", summary)
+	}
+	comments, err := annotated.GetComments(DefaultSheetName)
+	if err != nil || len(comments) != 1 || comments[0].Cell != "A3" {
+		t.Fatalf("unexpected comments: %v, %v", comments, err)
+	}
+	styleId, _ := annotated.GetCellStyle(DefaultSheetName, "A3")
+	style, err := annotated.GetStyle(styleId)
+	if err != nil || len(style.Fill.Color) != 1 || style.Fill.Color[0] != "FF0000" {
+		t.Fatalf("unexpected style: %v, %v", style, err)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/xuri/excelize/v2"
 	"regexp"
 	"strconv"
+	"strings"
+	"unicode"
 )
 
 const DefaultSheetName = "Sheet1"
@@ -17,13 +19,27 @@ const (
 	uniqueTag  = "unique"
 	dateTag    = "date"
 	mappingTag = "mapping"
+
+	requiredTag = "required"
+	minTag      = "min"
+	maxTag      = "max"
+	lenTag      = "len"
+	regexTag    = "regex"
+	emailTag    = "email"
+	oneofTag    = "oneof"
+	validateTag = "validate"
+	colTag      = "col"
+	indexTag    = "index"
+	extraTag    = "extra"
+	foldTag     = "fold"
+	defaultTag  = "default"
 )
 
 var (
 	urlRegex     = regexp.MustCompile(`^((https|http|ftp|rtsp|mms)?://)\S+$`)
-	nameRegex    = regexp.MustCompile(`name\((.*?)\)`)
 	mappingRegex = regexp.MustCompile(`mapping\((.*?)\)`)
 	widthRegex   = regexp.MustCompile(`width\((.*?)\)`)
+	emailRegex   = regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}$`)
 )
 
 type ExcelHeader struct {
@@ -180,3 +196,39 @@ func FrozenRow(xlsx *excelize.File, sheetName string, row int) {
 		ActivePane:  "bottomLeft",
 	})
 }
+
+// HeaderAliases 返回 name 标签中以|分隔的全部表头名称，第一个为导出时使用的名称
+func HeaderAliases(tag string) []string {
+	//与解析时一致按括号层级取值，名称中可以包含括号
+	name := parseTagOptions(tag)[nameTag]
+	var aliases []string
+	for _, alias := range strings.Split(name, "|") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+func headerName(tag string) string {
+	aliases := HeaderAliases(tag)
+	if len(aliases) == 0 {
+		return ""
+	}
+	return aliases[0]
+}
+
+// NormalizeHeader 统一表头的全角半角、大小写，并去除全部空白和换行
+func NormalizeHeader(header string) string {
+	var sb strings.Builder
+	for _, r := range header {
+		switch {
+		case r == '\u3000' || unicode.IsSpace(r):
+			continue
+		case r >= '\uFF01' && r <= '\uFF5E':
+			r -= 0xFEE0
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
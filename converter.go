@@ -0,0 +1,222 @@
+package dgexcel
+
+import (
+	"encoding"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CellMeta 单元格的上下文信息
+type CellMeta struct {
+	Sheet  string
+	Row    int
+	Column string
+	Header string
+	Field  reflect.StructField
+	// date 标签中的输入格式
+	DateLayouts []string
+	// 解析时间使用的时区
+	Location *time.Location
+	// 工作簿是否使用1904日期系统
+	Date1904 bool
+}
+
+type CellStyle = excelize.Style
+
+// ExcelCellUnmarshaler 自定义类型自行解析单元格，优先于转换器和基础类型
+type ExcelCellUnmarshaler interface {
+	UnmarshalExcelCell(raw string, meta CellMeta) error
+}
+
+// ExcelCellMarshaler 自定义类型自行输出单元格的值和样式，样式可为 nil
+type ExcelCellMarshaler interface {
+	MarshalExcelCell() (any, *CellStyle, error)
+}
+
+// Converter 非基础类型字段与单元格之间的双向转换，Parse 返回值须可赋值给注册的类型
+type Converter struct {
+	Parse  func(raw string, meta CellMeta) (any, error)
+	Format func(value any) (any, error)
+}
+
+var (
+	converters     = make(map[reflect.Type]*Converter)
+	convertersLock sync.RWMutex
+
+	cellUnmarshalerType = reflect.TypeOf((*ExcelCellUnmarshaler)(nil)).Elem()
+	cellMarshalerType   = reflect.TypeOf((*ExcelCellMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+var defaultTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	time.RFC3339,
+}
+
+func init() {
+	RegisterConverter(timeType, &Converter{
+		Parse: func(raw string, meta CellMeta) (any, error) {
+			return ParseExcelTime(raw, meta.DateLayouts, meta.Location, meta.Date1904)
+		},
+		Format: func(value any) (any, error) {
+			t := value.(time.Time)
+			if t.IsZero() {
+				return "", nil
+			}
+			return t.Format(time.DateTime), nil
+		},
+	})
+	RegisterConverter(reflect.TypeOf(time.Duration(0)), &Converter{
+		Parse: func(raw string, meta CellMeta) (any, error) {
+			return time.ParseDuration(raw)
+		},
+		Format: func(value any) (any, error) {
+			return value.(time.Duration).String(), nil
+		},
+	})
+	RegisterConverter(reflect.TypeOf((*big.Int)(nil)), &Converter{
+		Parse: func(raw string, meta CellMeta) (any, error) {
+			i, ok := new(big.Int).SetString(raw, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid number[%s]", raw)
+			}
+			return i, nil
+		},
+		Format: func(value any) (any, error) {
+			return value.(*big.Int).String(), nil
+		},
+	})
+	RegisterConverter(reflect.TypeOf((*big.Rat)(nil)), &Converter{
+		Parse: func(raw string, meta CellMeta) (any, error) {
+			r, ok := new(big.Rat).SetString(raw)
+			if !ok {
+				return nil, fmt.Errorf("invalid number[%s]", raw)
+			}
+			return r, nil
+		},
+		Format: func(value any) (any, error) {
+			r := value.(*big.Rat)
+			if r.IsInt() {
+				return r.Num().String(), nil
+			}
+			return strings.TrimRight(strings.TrimRight(r.FloatString(20), "0"), "."), nil
+		},
+	})
+}
+
+// RegisterConverter 注册类型转换器，同一类型重复注册时覆盖
+func RegisterConverter(typ reflect.Type, converter *Converter) {
+	convertersLock.Lock()
+	defer convertersLock.Unlock()
+	converters[typ] = converter
+}
+
+func getConverter(typ reflect.Type) (*Converter, bool) {
+	convertersLock.RLock()
+	defer convertersLock.RUnlock()
+	converter, ok := converters[typ]
+	return converter, ok
+}
+
+// convertCell 依次使用 ExcelCellUnmarshaler、转换器或 encoding.TextUnmarshaler 解析单元格，handled 为 false 时按基础类型处理
+func convertCell(val reflect.Value, col string, meta CellMeta) (handled bool, err error) {
+	if isCellUnmarshaler(val) {
+		return true, val.Addr().Interface().(ExcelCellUnmarshaler).UnmarshalExcelCell(col, meta)
+	}
+
+	if converter, ok := getConverter(val.Type()); ok && converter.Parse != nil {
+		if col == "" {
+			val.Set(reflect.Zero(val.Type()))
+			return true, nil
+		}
+		value, err := converter.Parse(col, meta)
+		if err != nil {
+			return true, err
+		}
+		//转换器返回 nil 或类型不匹配时按转换失败处理
+		rv := reflect.ValueOf(value)
+		if !rv.IsValid() || !rv.Type().AssignableTo(val.Type()) {
+			return true, fmt.Errorf("converter returned %T, expected %s", value, val.Type())
+		}
+		val.Set(rv)
+		return true, nil
+	}
+
+	if val.Kind() != reflect.Ptr && val.CanAddr() && val.Addr().Type().Implements(textUnmarshalerType) {
+		if col == "" {
+			val.Set(reflect.Zero(val.Type()))
+			return true, nil
+		}
+		return true, val.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(col))
+	}
+
+	return false, nil
+}
+
+func isCellUnmarshaler(val reflect.Value) bool {
+	return val.Kind() != reflect.Ptr && val.CanAddr() && val.Addr().Type().Implements(cellUnmarshalerType)
+}
+
+// marshalCell 使用 ExcelCellMarshaler 输出单元格的值和样式
+func marshalCell(val reflect.Value) (value any, style *CellStyle, handled bool, err error) {
+	if val.Type().Implements(cellMarshalerType) {
+		if val.Kind() == reflect.Ptr && val.IsNil() {
+			return "", nil, true, nil
+		}
+		value, style, err = val.Interface().(ExcelCellMarshaler).MarshalExcelCell()
+		return value, style, true, err
+	}
+	if val.CanAddr() && val.Addr().Type().Implements(cellMarshalerType) {
+		value, style, err = val.Addr().Interface().(ExcelCellMarshaler).MarshalExcelCell()
+		return value, style, true, err
+	}
+	return nil, nil, false, nil
+}
+
+// formatCell 使用转换器或 encoding.TextMarshaler 格式化字段值，handled 为 false 时按默认方式输出
+func formatCell(val reflect.Value) (value any, handled bool, err error) {
+	if converter, ok := getConverter(val.Type()); ok && converter.Format != nil {
+		if val.Kind() == reflect.Ptr && val.IsNil() {
+			return "", true, nil
+		}
+		value, err = converter.Format(val.Interface())
+		return value, true, err
+	}
+
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			if _, ok := getConverter(val.Type().Elem()); ok {
+				return "", true, nil
+			}
+			return nil, false, nil
+		}
+		if _, ok := getConverter(val.Type().Elem()); ok {
+			return formatCell(val.Elem())
+		}
+	}
+
+	if val.Type().Implements(textMarshalerType) {
+		if val.Kind() == reflect.Ptr && val.IsNil() {
+			return "", true, nil
+		}
+		text, err := val.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), true, err
+	}
+	if val.CanAddr() && val.Addr().Type().Implements(textMarshalerType) {
+		text, err := val.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), true, err
+	}
+
+	return nil, false, nil
+}
@@ -0,0 +1,275 @@
+package dgexcel
+
+import (
+	"errors"
+	"fmt"
+	dgctx "github.com/darwinOrg/go-common/context"
+	"github.com/xuri/excelize/v2"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Order struct {
+	No        string        `excel:"name(订单号)"`
+	Amount    *big.Rat      `excel:"name(金额)"`
+	CreatedAt time.Time     `excel:"name(下单时间)"`
+	Timeout   time.Duration `excel:"name(超时时长)"`
+	PaidAt    *time.Time    `excel:"name(支付时间)"`
+}
+
+func TestConverters(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "订单号"}, {Name: "金额"}, {Name: "下单时间"}, {Name: "超时时长"}, {Name: "支付时间"}},
+		Datas:   [][]any{{"O001", "12.5", "2024-03-11 10:00:00", "30m", "2024-03-11"}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var orders []*Order
+	err = BindExcelReaderEach[Order](ctx, buf, nil, func(order *Order, rowNum int) error {
+		orders = append(orders, order)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("bind orders error: %v", err)
+	}
+	order := orders[0]
+	if order.Amount.FloatString(1) != "12.5" || order.CreatedAt.Hour() != 10 || order.Timeout != 30*time.Minute || order.PaidAt == nil {
+		t.Fatalf("unexpected order: %+v", order)
+	}
+
+	values, err := getTagValMap(order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[1].text != "12.5" || values[2].text != "2024-03-11 10:00:00" || values[3].text != "30m0s" {
+		t.Fatalf("unexpected export values: %v", values)
+	}
+}
+
+type Grade string
+
+type Student struct {
+	Name  string `excel:"name(姓名)"`
+	Grade Grade  `excel:"name(等级)"`
+}
+
+func TestConverterInvalidResult(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	RegisterConverter(reflect.TypeOf(Grade("")), &Converter{
+		Parse: func(raw string, meta CellMeta) (any, error) {
+			switch raw {
+			case "无":
+				return nil, nil
+			case "1":
+				return 1, nil
+			default:
+				return Grade(raw), nil
+			}
+		},
+	})
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "姓名"}, {Name: "等级"}},
+		Datas:   [][]any{{"张三", "A"}, {"李四", "无"}, {"王五", "1"}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := BindExcelReader2StructPartial[Student](ctx, buf, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr.Rows) != 1 || pr.Rows[0].Data.Grade != "A" {
+		t.Fatalf("unexpected rows: %v", pr.Rows)
+	}
+	if pr.Errors == nil || len(pr.Errors.CellErrors) != 2 || pr.Errors.CellErrors[0].Code != CellErrorType || pr.Errors.CellErrors[1].Code != CellErrorType {
+		t.Fatalf("unexpected errors: %v", pr.Errors)
+	}
+}
+
+type TaskStatus int
+
+func (s *TaskStatus) UnmarshalExcelCell(raw string, meta CellMeta) error {
+	switch raw {
+	case "进行中":
+		*s = 1
+	case "已完成":
+		*s = 2
+	default:
+		return fmt.Errorf("%s[%s]非法", meta.Header, raw)
+	}
+	return nil
+}
+
+func (s TaskStatus) MarshalExcelCell() (any, *CellStyle, error) {
+	switch s {
+	case 1:
+		return "进行中", nil, nil
+	case 2:
+		return "已完成", &CellStyle{Font: &excelize.Font{Color: "00AA00"}}, nil
+	}
+	return nil, nil, fmt.Errorf("unknown task status[%d]", s)
+}
+
+type Task struct {
+	Title  string     `excel:"name(任务)"`
+	Status TaskStatus `excel:"name(状态)"`
+}
+
+func TestExcelCellMarshaler(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "任务"}, {Name: "状态"}},
+		Datas:   [][]any{{"导入", "已完成"}, {"导出", "未开始"}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = BindExcelBytes2Struct[Task](ctx, buf.Bytes(), 1, 2)
+	var cellErr *CellError
+	if !errors.As(err, &cellErr) || cellErr.Row != 3 || cellErr.Code != CellErrorType {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	xlsx, err := ExportStruct2Xlsx([]*Task{{Title: "导入", Status: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := xlsx.GetCellValue(DefaultSheetName, "B2"); v != "已完成" {
+		t.Fatalf("unexpected exported status: %s", v)
+	}
+
+	if _, err = ExportStruct2Xlsx([]*Task{{Title: "导出", Status: 3}}); err == nil {
+		t.Fatal("expected marshal error")
+	}
+}
+
+type Shipment struct {
+	No        string     `excel:"name(运单号)"`
+	ShippedAt time.Time  `excel:"name(发货日期);date(2006/01/02|02.01.2006)"`
+	SignedAt  *time.Time `excel:"name(签收日期)"`
+}
+
+func TestExcelSerialDate(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "运单号"}, {Name: "发货日期"}, {Name: "签收日期"}},
+		Datas:   [][]any{{"S001", 45321, "2024-01-30"}, {"S002", "31.01.2024", 45322.5}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shanghai := time.FixedZone("CST", 8*3600)
+	shipments, err := BindExcelBytes2Struct[Shipment](ctx, buf.Bytes(), 1, 2, &BindOptions{TimeZone: shanghai})
+	if err != nil {
+		t.Fatalf("bind shipments error: %v", err)
+	}
+	if got := shipments[0].ShippedAt.Format(time.DateOnly); got != "2024-01-30" {
+		t.Fatalf("unexpected serial date: %s", got)
+	}
+	if got := shipments[1].ShippedAt.Format(time.DateOnly); got != "2024-01-31" {
+		t.Fatalf("unexpected layout date: %s", got)
+	}
+	if got := shipments[1].SignedAt.In(shanghai).Format(time.DateTime); got != "2024-01-31 12:00:00" {
+		t.Fatalf("unexpected serial datetime: %s", got)
+	}
+}
+
+type Delivery struct {
+	No          string    `excel:"name(运单号)"`
+	ShippedAt   time.Time `excel:"name(发货日期)"`
+	DeliveredOn string    `excel:"name(送达日期);date(2006-01-02,2006/01/02)"`
+}
+
+func TestExcelSerialDateRange(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "运单号"}, {Name: "发货日期"}, {Name: "送达日期"}},
+		Datas:   [][]any{{"S001", "20240130", "20240130"}, {"S002", "1", "1"}, {"S003", 45321, 45322}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = BindExcelBytes2Struct[Delivery](ctx, buf.Bytes(), 1, 2)
+	var importErr *ImportError
+	if !errors.As(err, &importErr) {
+		t.Fatalf("expected import error, got %v", err)
+	}
+	if len(importErr.CellErrors) != 4 {
+		t.Fatalf("unexpected cell errors: %v", importErr.CellErrors)
+	}
+	for _, ce := range importErr.CellErrors {
+		if ce.Code != CellErrorDate || ce.Row > 3 {
+			t.Fatalf("unexpected cell error: %+v", ce)
+		}
+	}
+
+	deliveries, err := BindExcelBytes2Struct[Delivery](ctx, buf.Bytes(), 1, 4)
+	if err != nil {
+		t.Fatalf("bind deliveries error: %v", err)
+	}
+	if got := deliveries[0].ShippedAt.Format(time.DateOnly); got != "2024-01-30" {
+		t.Fatalf("unexpected serial date: %s", got)
+	}
+	if got := deliveries[0].DeliveredOn; got != "2024/01/31" {
+		t.Fatalf("unexpected serial date text: %s", got)
+	}
+}
+
+func TestExcelDateFormattedCell(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	xlsx := excelize.NewFile()
+	WriteRowDatas(xlsx, DefaultSheetName, 0, 0, 0, "运单号", "发货日期", "签收日期")
+	_ = xlsx.SetCellValue(DefaultSheetName, "A2", "S001")
+	_ = xlsx.SetCellValue(DefaultSheetName, "B2", 45321)
+	dateStyleId, err := xlsx.NewStyle(&excelize.Style{NumFmt: 14})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = xlsx.SetCellStyle(DefaultSheetName, "B2", "B2", dateStyleId)
+	_ = xlsx.SetCellValue(DefaultSheetName, "C2", time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC))
+	buf, err := xlsx.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shanghai := time.FixedZone("CST", 8*3600)
+	shipments, err := BindExcelBytes2Struct[Shipment](ctx, buf.Bytes(), 1, 2, &BindOptions{TimeZone: shanghai})
+	if err != nil {
+		t.Fatalf("bind shipments error: %v", err)
+	}
+	if got := shipments[0].ShippedAt.Format(time.DateOnly); got != "2024-01-30" {
+		t.Fatalf("unexpected formatted date: %s", got)
+	}
+	if got := shipments[0].SignedAt.In(shanghai).Format(time.DateTime); got != "2024-01-31 12:00:00" {
+		t.Fatalf("unexpected formatted datetime: %s", got)
+	}
+}
+
+func TestConvertersUsingTargetBuilder(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "订单号"}, {Name: "金额"}, {Name: "下单时间"}, {Name: "超时时长"}, {Name: "支付时间"}},
+		Datas:   [][]any{{"O001", "12.5", "2024-03-11 10:00:00", "30m", "2024-03-11"}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orders, err := BindExcelReaderUsingTargetBuilder(ctx, buf, 1, 2, func() any { return new(Order) })
+	if err != nil {
+		t.Fatalf("bind orders error: %v", err)
+	}
+	order, ok := orders[0].(*Order)
+	if !ok || order.Amount.FloatString(1) != "12.5" || order.Timeout != 30*time.Minute || order.PaidAt == nil {
+		t.Fatalf("unexpected order: %+v", orders[0])
+	}
+}
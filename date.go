@@ -0,0 +1,82 @@
+package dgexcel
+
+import (
+	"errors"
+	"github.com/xuri/excelize/v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Excel 日期序列号的有效范围，上限对应 9999-12-31；1900日期系统中60及以前的序列号受1900闰年问题影响，不作为日期解析
+const (
+	minExcelSerial     = 61
+	minExcelSerial1904 = 0
+	maxExcelSerial     = 2958465
+	maxExcelSerial1904 = 2957003
+)
+
+// ParseExcelTime 按给定格式依次解析，均不匹配时按 Excel 日期序列号（1900或1904日期系统）解析
+func ParseExcelTime(raw string, layouts []string, loc *time.Location, date1904 bool) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	if len(layouts) == 0 {
+		layouts = defaultTimeLayouts
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	serial, err := strconv.ParseFloat(raw, 64)
+	if err != nil || !validExcelSerial(serial, date1904) {
+		return time.Time{}, errors.New("invalid date: " + raw)
+	}
+	t, err := excelize.ExcelDateToTime(serial, date1904)
+	if err != nil {
+		return time.Time{}, err
+	}
+	//序列号不含时区，按墙上时间解释
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc), nil
+}
+
+func validExcelSerial(serial float64, date1904 bool) bool {
+	if date1904 {
+		return serial >= minExcelSerial1904 && serial < maxExcelSerial1904+1
+	}
+	return serial >= minExcelSerial && serial < maxExcelSerial+1
+}
+
+// splitDateTag 拆分 date 标签，格式为 输入格式1|输入格式2,输出格式
+func splitDateTag(format string) ([]string, string) {
+	if format == "" {
+		return nil, ""
+	}
+	formats := strings.SplitN(format, ",", 2)
+	var layouts []string
+	for _, layout := range strings.Split(formats[0], "|") {
+		if layout = strings.TrimSpace(layout); layout != "" {
+			layouts = append(layouts, layout)
+		}
+	}
+	if len(formats) == 1 {
+		return layouts, ""
+	}
+	return layouts, formats[1]
+}
+
+func isTimeField(field reflect.StructField) bool {
+	typ := field.Type
+	if typ == nil {
+		return false
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ == timeType
+}
@@ -0,0 +1,125 @@
+package dgexcel
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type CellErrorCode string
+
+const (
+	CellErrorUnique   CellErrorCode = "unique"
+	CellErrorDate     CellErrorCode = "date"
+	CellErrorMapping  CellErrorCode = "mapping"
+	CellErrorType     CellErrorCode = "type"
+	CellErrorRequired CellErrorCode = "required"
+	CellErrorValidate CellErrorCode = "validate"
+	// 行级钩子返回的错误
+	CellErrorRow CellErrorCode = "row"
+)
+
+// CellError 单元格级别的导入错误，Message 为渲染后的提示文本
+type CellError struct {
+	Sheet   string
+	Row     int
+	Column  string
+	Header  string
+	Value   string
+	Field   string
+	Code    CellErrorCode
+	Message string
+}
+
+func newCellError(code CellErrorCode, message string) *CellError {
+	return &CellError{Code: code, Message: message}
+}
+
+func (e *CellError) Error() string {
+	return e.Message
+}
+
+// ImportError 汇总一次导入的全部单元格错误，可通过 errors.As 取出 *ImportError 或 *CellError
+type ImportError struct {
+	CellErrors []*CellError
+	withSheet  bool
+	messages   *messageCatalog
+	//各工作表的表头行，用于在原文件上标注错误
+	headerRows map[string]int
+	//多工作表导入时各工作表的提示语，未设置的使用 messages
+	sheetMessages map[string]*messageCatalog
+}
+
+func newImportError(cellErrors []*CellError, messages *messageCatalog) *ImportError {
+	sortCellErrors(cellErrors)
+	return &ImportError{CellErrors: cellErrors, messages: messages}
+}
+
+// HeaderRow 返回工作表导入时使用的表头行，无表头时为0
+func (e *ImportError) HeaderRow(sheetName string) int {
+	return e.headerRows[sheetName]
+}
+
+func (e *ImportError) withHeaderRow(sheetName string, headerRow int) *ImportError {
+	if e.headerRows == nil {
+		e.headerRows = make(map[string]int)
+	}
+	e.headerRows[sheetName] = headerRow
+	return e
+}
+
+func (e *ImportError) Error() string {
+	msgs := make([]string, 0, len(e.CellErrors))
+	for _, ce := range e.CellErrors {
+		messages, ok := e.sheetMessages[ce.Sheet]
+		if !ok {
+			messages = e.messages
+		}
+		msg := messages.render(MsgRowPrefix, map[string]string{"row": strconv.Itoa(ce.Row), "message": ce.Message})
+		if e.withSheet {
+			msg = messages.render(MsgSheetPrefix, map[string]string{"sheet": ce.Sheet, "message": msg})
+		}
+		msgs = append(msgs, msg)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e *ImportError) Unwrap() []error {
+	errs := make([]error, 0, len(e.CellErrors))
+	for _, ce := range e.CellErrors {
+		errs = append(errs, ce)
+	}
+	return errs
+}
+
+func sortCellErrors(cellErrors []*CellError) {
+	sort.SliceStable(cellErrors, func(i, j int) bool {
+		if cellErrors[i].Sheet != cellErrors[j].Sheet {
+			return cellErrors[i].Sheet < cellErrors[j].Sheet
+		}
+		return cellErrors[i].Row < cellErrors[j].Row
+	})
+}
+
+// HeaderError 表头校验失败，在解析数据行之前返回
+type HeaderError struct {
+	Sheet      string
+	Missing    []string
+	Unknown    []string
+	Duplicates []string
+	messages   *messageCatalog
+}
+
+func (e *HeaderError) Error() string {
+	var msgs []string
+	if len(e.Missing) > 0 {
+		msgs = append(msgs, e.messages.render(MsgHeaderMissing, map[string]string{"columns": strings.Join(e.Missing, ",")}))
+	}
+	if len(e.Unknown) > 0 {
+		msgs = append(msgs, e.messages.render(MsgHeaderUnknown, map[string]string{"columns": strings.Join(e.Unknown, ",")}))
+	}
+	if len(e.Duplicates) > 0 {
+		msgs = append(msgs, e.messages.render(MsgHeaderDuplicate, map[string]string{"columns": strings.Join(e.Duplicates, ",")}))
+	}
+	return strings.Join(msgs, "\n")
+}
@@ -8,19 +8,31 @@ import (
 	"github.com/xuri/excelize/v2"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 func ExportStruct2Xlsx(v any) (*excelize.File, error) {
 	tagList := getStructTagList(v, excelTag)
-	mapTagList := struct2MapTagList(v)
+	mapTagList, err := struct2MapTagList(v)
+	if err != nil {
+		return nil, err
+	}
 	xlsx := excelize.NewFile()
 	_, _ = xlsx.NewSheet(DefaultSheetName)
 	centerStyleId := BuildCenterStyleId(xlsx)
-
-	for c, tagVal := range tagList {
-		name, _ := stringMatchExport(tagVal, nameRegex)
+	//excel:"extra" 字段展开为动态列，追加在固定列之后
+	extraIndex := extraFieldIndex(v)
+	extraList := extraValues(v, extraIndex)
+	extraHeaders := extraKeys(extraList)
+
+	c := 0
+	for i, tagVal := range tagList {
+		if i == extraIndex {
+			continue
+		}
+		name := headerName(tagVal)
 
 		width, _ := stringMatchExport(tagVal, widthRegex)
 		if width == "" {
@@ -35,35 +47,37 @@ func ExportStruct2Xlsx(v any) (*excelize.File, error) {
 		cellIndex := ColumnIndexToName(c) + "1"
 		_ = xlsx.SetCellValue(DefaultSheetName, cellIndex, name)
 		_ = xlsx.SetCellStyle(DefaultSheetName, cellIndex, cellIndex, centerStyleId)
+		c++
+	}
+	for _, name := range extraHeaders {
+		_ = xlsx.SetColWidth(DefaultSheetName, ColumnIndexToName(c), ColumnIndexToName(c), 20)
+		cellIndex := ColumnIndexToName(c) + "1"
+		_ = xlsx.SetCellValue(DefaultSheetName, cellIndex, name)
+		_ = xlsx.SetCellStyle(DefaultSheetName, cellIndex, cellIndex, centerStyleId)
+		c++
 	}
 
 	for r, mapTagVal := range mapTagList {
 		c := 0
-		for i, tagVal := range mapTagVal {
-			tagKey := tagList[i]
-			mapping, _ := stringMatchExport(tagKey, mappingRegex)
-			if mapping != "" {
-				formatStr := strings.Split(mapping, ",")
-				for _, format := range formatStr {
-					n := strings.SplitN(format, ":", 2)
-					if len(n) != 2 {
-						continue
-					}
-					if n[1] == tagVal {
-						tagVal = n[0]
-					}
-				}
+		for i, cell := range mapTagVal {
+			if i == extraIndex {
+				continue
 			}
+			tagKey := tagList[i]
+			tagVal := exportMappingText(tagKey, cell.text)
 
 			cellIndex := ColumnIndexToName(c) + strconv.Itoa(r+2)
-			if urlRegex.MatchString(tagVal) {
-				_ = xlsx.SetCellFormula(DefaultSheetName, cellIndex, fmt.Sprintf("=HYPERLINK(\"%s\", \"%s\")", tagVal, tagVal))
-			} else {
-				_ = xlsx.SetCellValue(DefaultSheetName, cellIndex, tagVal)
-			}
+			writeExportCell(xlsx, DefaultSheetName, cellIndex, cell, tagVal)
+			applyExportCellStyle(xlsx, DefaultSheetName, cellIndex, cell)
 
 			c++
 		}
+		for _, name := range extraHeaders {
+			tagVal := extraList[r][name]
+			cellIndex := ColumnIndexToName(c) + strconv.Itoa(r+2)
+			writeExportCell(xlsx, DefaultSheetName, cellIndex, &exportCell{text: tagVal}, tagVal)
+			c++
+		}
 	}
 
 	FrozenFirstRow(xlsx, DefaultSheetName)
@@ -95,48 +109,54 @@ func ExportStruct2XlsxByTemplate(v any, templateFilePath string, headerRow int)
 	headers := rows[headerRow]
 
 	tagList := getStructTagList(v, excelTag)
-	mapTagList := struct2MapTagList(v)
+	mapTagList, err := struct2MapTagList(v)
+	if err != nil {
+		return nil, err
+	}
+	extraIndex := extraFieldIndex(v)
+	extraList := extraValues(v, extraIndex)
 
 	for r, mapTagVal := range mapTagList {
-		for i, tagVal := range mapTagVal {
-			tagKey := tagList[i]
-			mapping, _ := stringMatchExport(tagKey, mappingRegex)
-			if mapping != "" {
-				formatStr := strings.Split(mapping, ",")
-				for _, format := range formatStr {
-					n := strings.SplitN(format, ":", 2)
-					if len(n) != 2 {
-						continue
-					}
-					if n[1] == tagVal {
-						tagVal = n[0]
-					}
-				}
+		for i, cell := range mapTagVal {
+			if i == extraIndex {
+				continue
 			}
+			tagKey := tagList[i]
+			tagVal := exportMappingText(tagKey, cell.text)
 
-			name, _ := stringMatchExport(tagKey, nameRegex)
-			if name == "" {
+			aliases := HeaderAliases(tagKey)
+			if len(aliases) == 0 {
 				continue
 			}
 
 			for c, header := range headers {
-				if header == name {
+				if matchHeader(header, aliases) {
 					cellIndex := ColumnIndexToName(c) + strconv.Itoa(r+2)
-					if urlRegex.MatchString(tagVal) {
-						_ = xlsx.SetCellFormula(firstSheetName, cellIndex, fmt.Sprintf("=HYPERLINK(\"%s\", \"%s\")", tagVal, tagVal))
-					} else {
-						_ = xlsx.SetCellValue(firstSheetName, cellIndex, tagVal)
-					}
+					writeExportCell(xlsx, firstSheetName, cellIndex, cell, tagVal)
 
 					cellStyle, err := xlsx.GetCellStyle(firstSheetName, ColumnIndexToName(c)+"2")
 					if err == nil {
 						_ = xlsx.SetCellStyle(firstSheetName, cellIndex, cellIndex, cellStyle)
 					}
+					applyExportCellStyle(xlsx, firstSheetName, cellIndex, cell)
 
 					break
 				}
 			}
 		}
+		//extra 字段按模板表头写入
+		if extraIndex < 0 {
+			continue
+		}
+		for name, tagVal := range extraList[r] {
+			for c, header := range headers {
+				if matchHeader(header, []string{name}) {
+					cellIndex := ColumnIndexToName(c) + strconv.Itoa(r+2)
+					writeExportCell(xlsx, firstSheetName, cellIndex, &exportCell{text: tagVal}, tagVal)
+					break
+				}
+			}
+		}
 	}
 
 	return xlsx, nil
@@ -197,9 +217,70 @@ func getStructTagList(v any, tag string) []string {
 	return resList
 }
 
-func getTagValMap(v any) []string {
+type exportCell struct {
+	text  string
+	value any
+	style *CellStyle
+}
+
+// exportMappingText 按 mapping(无效:0,有效:1) 将存储值还原为显示值
+func exportMappingText(tagKey, tagVal string) string {
+	mapping, _ := stringMatchExport(tagKey, mappingRegex)
+	if mapping == "" {
+		return tagVal
+	}
+	for _, format := range strings.Split(mapping, ",") {
+		n := strings.SplitN(format, ":", 2)
+		if len(n) != 2 {
+			continue
+		}
+		if n[1] == tagVal {
+			tagVal = n[0]
+		}
+	}
+	return tagVal
+}
+
+func writeExportCell(xlsx *excelize.File, sheetName, cellIndex string, cell *exportCell, tagVal string) {
+	if urlRegex.MatchString(tagVal) {
+		_ = xlsx.SetCellFormula(sheetName, cellIndex, fmt.Sprintf("=HYPERLINK(\"%s\", \"%s\")", tagVal, tagVal))
+	} else if cell.value != nil && tagVal == cell.text {
+		_ = xlsx.SetCellValue(sheetName, cellIndex, cell.value)
+	} else {
+		_ = xlsx.SetCellValue(sheetName, cellIndex, tagVal)
+	}
+}
+
+func applyExportCellStyle(xlsx *excelize.File, sheetName, cellIndex string, cell *exportCell) {
+	if cell.style == nil {
+		return
+	}
+	styleId, err := xlsx.NewStyle(cell.style)
+	if err == nil {
+		_ = xlsx.SetCellStyle(sheetName, cellIndex, cellIndex, styleId)
+	}
+}
+
+// newExportCell 依次按自描述类型、自定义类型转换和默认格式生成导出单元格
+func newExportCell(val reflect.Value) (*exportCell, error) {
+	if value, style, handled, err := marshalCell(val); handled {
+		if err != nil {
+			return nil, fmt.Errorf("marshal excel cell error: %w", err)
+		}
+		return &exportCell{text: fmt.Sprintf("%v", value), value: value, style: style}, nil
+	}
+	if value, handled, err := formatCell(val); handled {
+		if err != nil {
+			return nil, fmt.Errorf("format excel cell error: %w", err)
+		}
+		return &exportCell{text: fmt.Sprintf("%v", value)}, nil
+	}
+	return &exportCell{text: fmt.Sprintf("%v", val.Interface())}, nil
+}
+
+func getTagValMap(v any) ([]*exportCell, error) {
 	if v == nil {
-		return []string{}
+		return []*exportCell{}, nil
 	}
 
 	isPtr := false
@@ -209,7 +290,7 @@ func getTagValMap(v any) []string {
 		isPtr = true
 	}
 
-	var resMap []string
+	var resMap []*exportCell
 	fieldNum := typeOf.NumField()
 	for i := 0; i < fieldNum; i++ {
 		structField := typeOf.Field(i)
@@ -218,27 +299,112 @@ func getTagValMap(v any) []string {
 			rv = rv.Elem()
 		}
 		val := rv.FieldByName(structField.Name)
-		resMap = append(resMap, fmt.Sprintf("%v", val.Interface()))
+		cell, err := newExportCell(val)
+		if err != nil {
+			return nil, fmt.Errorf("field[%s] %w", structField.Name, err)
+		}
+		resMap = append(resMap, cell)
 	}
 
-	return resMap
+	return resMap, nil
 }
 
-func struct2MapTagList(v any) [][]string {
-	var resList [][]string
+func struct2MapTagList(v any) ([][]*exportCell, error) {
+	var resList [][]*exportCell
 	switch reflect.TypeOf(v).Kind() {
 	case reflect.Slice, reflect.Array:
 		values := reflect.ValueOf(v)
 		for i := 0; i < values.Len(); i++ {
-			resList = append(resList, getTagValMap(values.Index(i).Interface()))
+			cells, err := getTagValMap(values.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			resList = append(resList, cells)
 		}
-		break
 	case reflect.Struct:
-		val := reflect.ValueOf(v).Interface()
-		resList = append(resList, getTagValMap(val))
-		break
+		cells, err := getTagValMap(reflect.ValueOf(v).Interface())
+		if err != nil {
+			return nil, err
+		}
+		resList = append(resList, cells)
 	default:
 		dglogger.Errorf(dgctx.SimpleDgContext(), "type %v not support", reflect.TypeOf(v).Kind())
 	}
+	return resList, nil
+}
+
+// extraFieldIndex 返回 excel:"extra" 标记的 map[string]string 字段下标，不存在时返回-1
+func extraFieldIndex(v any) int {
+	if v == nil {
+		return -1
+	}
+	typeOf := reflect.TypeOf(v)
+	if typeOf.Kind() == reflect.Slice || typeOf.Kind() == reflect.Array {
+		typeOf = typeOf.Elem()
+	}
+	if typeOf.Kind() == reflect.Ptr {
+		typeOf = typeOf.Elem()
+	}
+	if typeOf.Kind() != reflect.Struct {
+		return -1
+	}
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if _, ok := parseTagOptions(field.Tag.Get(excelTag))[extraTag]; ok && field.Type == extraType {
+			return i
+		}
+	}
+	return -1
+}
+
+func extraValues(v any, extraIndex int) []map[string]string {
+	var resList []map[string]string
+	if extraIndex < 0 {
+		return resList
+	}
+	fieldValue := func(item reflect.Value) map[string]string {
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				return nil
+			}
+			item = item.Elem()
+		}
+		return item.Field(extraIndex).Interface().(map[string]string)
+	}
+	values := reflect.ValueOf(v)
+	switch values.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < values.Len(); i++ {
+			resList = append(resList, fieldValue(values.Index(i)))
+		}
+	default:
+		resList = append(resList, fieldValue(values))
+	}
 	return resList
 }
+
+// extraKeys 汇总所有行的动态列名，按名称排序保证列顺序稳定
+func extraKeys(extraList []map[string]string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, extra := range extraList {
+		for key := range extra {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func matchHeader(header string, aliases []string) bool {
+	normalized := NormalizeHeader(header)
+	for _, alias := range aliases {
+		if NormalizeHeader(alias) == normalized {
+			return true
+		}
+	}
+	return false
+}
@@ -1,76 +1,127 @@
 package dgexcel
 
 import (
-	"fmt"
-	dgcoll "github.com/darwinOrg/go-common/collection"
+	"bytes"
 	dgctx "github.com/darwinOrg/go-common/context"
 	dgerr "github.com/darwinOrg/go-common/enums/error"
 	dglogger "github.com/darwinOrg/go-logger"
+	"io"
+	"mime/multipart"
 	"os"
-	"strings"
 )
 
-func SimpleBindExcel2Struct[T any](ctx *dgctx.DgContext, filePath string) ([]*T, error) {
-	return BindExcel2Struct[T](ctx, filePath, 1, 2)
+func SimpleBindExcel2Struct[T any](ctx *dgctx.DgContext, filePath string, opts ...*BindOptions) ([]*T, error) {
+	return BindExcel2Struct[T](ctx, filePath, 1, 2, opts...)
 }
 
-func BindExcelUsingTargetBuilder(ctx *dgctx.DgContext, filePath string, headerRow int, dataStartRow int, targetBuilderFn func() any) ([]any, error) {
-	file, err := os.Open(filePath)
+func BindExcelUsingTargetBuilder(ctx *dgctx.DgContext, filePath string, headerRow int, dataStartRow int, targetBuilderFn func() any, opts ...*BindOptions) ([]any, error) {
+	var ts []any
+	err := withExcelFile(ctx, filePath, func(file *os.File) (err error) {
+		ts, err = BindExcelReaderUsingTargetBuilder(ctx, file, headerRow, dataStartRow, targetBuilderFn, opts...)
+		return err
+	})
+	return ts, err
+}
+
+func BindExcelReaderUsingTargetBuilder(ctx *dgctx.DgContext, reader io.Reader, headerRow int, dataStartRow int, targetBuilderFn func() any, opts ...*BindOptions) ([]any, error) {
+	p, err := newParser(targetBuilderFn())
 	if err != nil {
-		dglogger.Errorf(ctx, "open excel file error: %v", err)
+		dglogger.Errorf(ctx, "new parser error: %v", err)
 		return nil, err
 	}
-	defer func(f *os.File) {
+
+	bindOpts := mergeBindOptions(ctx, headerRow, dataStartRow, opts)
+	rt, err := p.ParseContent(reader, bindOpts)
+	if err != nil {
+		dglogger.Errorf(ctx, "parse content error: %v", err)
+		return nil, err
+	}
+	logParsedHeader(ctx, p, bindOpts)
+	if cellErrors := rt.CellErrors(); len(cellErrors) != 0 {
+		return nil, importError(ctx, cellErrors, bindOpts).withHeaderRow(p.sheetName, p.headerRow)
+	}
+
+	//解析结果已是 targetBuilderFn 返回的类型，直接返回避免 json 转换丢失自定义类型
+	return rt.mappingResults, nil
+}
+
+func BindExcel2Struct[T any](ctx *dgctx.DgContext, filePath string, headerRow int, dataStartRow int, opts ...*BindOptions) ([]*T, error) {
+	var ts []*T
+	err := withExcelFile(ctx, filePath, func(file *os.File) (err error) {
+		ts, err = BindExcelReader2Struct[T](ctx, file, headerRow, dataStartRow, opts...)
+		return err
+	})
+	return ts, err
+}
+
+func BindExcelBytes2Struct[T any](ctx *dgctx.DgContext, data []byte, headerRow int, dataStartRow int, opts ...*BindOptions) ([]*T, error) {
+	return BindExcelReader2Struct[T](ctx, bytes.NewReader(data), headerRow, dataStartRow, opts...)
+}
+
+func BindExcelMultipart2Struct[T any](ctx *dgctx.DgContext, fileHeader *multipart.FileHeader, headerRow int, dataStartRow int, opts ...*BindOptions) ([]*T, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		dglogger.Errorf(ctx, "open multipart file error: %v", err)
+		return nil, err
+	}
+	defer func(f multipart.File) {
 		err := f.Close()
 		if err != nil {
-			dglogger.Errorf(ctx, "close excel file error: %v", err)
+			dglogger.Errorf(ctx, "close multipart file error: %v", err)
 		}
 	}(file)
 
-	p, err := newParser(targetBuilderFn())
+	return BindExcelReader2Struct[T](ctx, file, headerRow, dataStartRow, opts...)
+}
+
+func BindExcelReader2Struct[T any](ctx *dgctx.DgContext, reader io.Reader, headerRow int, dataStartRow int, opts ...*BindOptions) ([]*T, error) {
+	t := new(T)
+	p, err := newParser(t)
 	if err != nil {
 		dglogger.Errorf(ctx, "new parser error: %v", err)
 		return nil, err
 	}
 
-	rt, err := p.ParseContent(file, headerRow, dataStartRow)
+	bindOpts := mergeBindOptions(ctx, headerRow, dataStartRow, opts)
+	rt, err := p.ParseContent(reader, bindOpts)
 	if err != nil {
 		dglogger.Errorf(ctx, "parse content error: %v", err)
 		return nil, err
 	}
-	errList, has := rt.HasError()
-	if has {
-		var errs []string
-		for k, v := range errList {
-			dglogger.Warn(ctx, k, v)
-			egs := dgcoll.MapToList(v, func(s string) string { return fmt.Sprintf("第%d行：%s", k, s) })
-			errs = append(errs, egs...)
-		}
-		return nil, dgerr.SimpleDgError(strings.Join(errs, "\n"))
+	logParsedHeader(ctx, p, bindOpts)
+	if cellErrors := rt.CellErrors(); len(cellErrors) != 0 {
+		return nil, importError(ctx, cellErrors, bindOpts).withHeaderRow(p.sheetName, p.headerRow)
 	}
 
-	ts, err := rt.FormatBaseTargetBuilder(targetBuilderFn)
-	if err != nil {
-		dglogger.Errorf(ctx, "bind to struct error: %v", err)
-		return nil, err
+	//直接断言而非 json 转换，避免自定义类型在序列化时丢失
+	ts := make([]*T, 0, len(rt.mappingResults))
+	for _, elem := range rt.mappingResults {
+		ts = append(ts, elem.(*T))
 	}
 
 	return ts, nil
 }
 
-func BindExcel2Struct[T any](ctx *dgctx.DgContext, filePath string, headerRow int, dataStartRow int) ([]*T, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		dglogger.Errorf(ctx, "open excel file error: %v", err)
-		return nil, err
-	}
-	defer func(f *os.File) {
-		err := f.Close()
-		if err != nil {
-			dglogger.Errorf(ctx, "close excel file error: %v", err)
-		}
-	}(file)
+// PartialResult 部分成功导入的结果，Errors 为空时全部行绑定成功
+type PartialResult[T any] struct {
+	Rows   []*ImportRow[*T]
+	Errors *ImportError
+	// 实际使用的表头行与数据起始行，自动识别表头时为识别结果
+	HeaderRow    int
+	DataStartRow int
+}
 
+func BindExcel2StructPartial[T any](ctx *dgctx.DgContext, filePath string, headerRow int, dataStartRow int, opts ...*BindOptions) (*PartialResult[T], error) {
+	var pr *PartialResult[T]
+	err := withExcelFile(ctx, filePath, func(file *os.File) (err error) {
+		pr, err = BindExcelReader2StructPartial[T](ctx, file, headerRow, dataStartRow, opts...)
+		return err
+	})
+	return pr, err
+}
+
+// BindExcelReader2StructPartial 返回所有校验通过的行及其行号，错误行通过 PartialResult.Errors 返回，只有文件或表头问题才返回 error
+func BindExcelReader2StructPartial[T any](ctx *dgctx.DgContext, reader io.Reader, headerRow int, dataStartRow int, opts ...*BindOptions) (*PartialResult[T], error) {
 	t := new(T)
 	p, err := newParser(t)
 	if err != nil {
@@ -78,30 +129,90 @@ func BindExcel2Struct[T any](ctx *dgctx.DgContext, filePath string, headerRow in
 		return nil, err
 	}
 
-	rt, err := p.ParseContent(file, headerRow, dataStartRow)
+	bindOpts := mergeBindOptions(ctx, headerRow, dataStartRow, opts)
+	rt, err := p.ParseContent(reader, bindOpts)
 	if err != nil {
 		dglogger.Errorf(ctx, "parse content error: %v", err)
 		return nil, err
 	}
-	errList, has := rt.HasError()
-	if has {
-		var errs []string
-		for k, v := range errList {
-			dglogger.Warn(ctx, k, v)
-			egs := dgcoll.MapToList(v, func(s string) string { return fmt.Sprintf("第%d行：%s", k, s) })
-			errs = append(errs, egs...)
+
+	logParsedHeader(ctx, p, bindOpts)
+
+	pr := &PartialResult[T]{Rows: make([]*ImportRow[*T], 0, len(rt.validRows)), HeaderRow: rt.HeaderRow(), DataStartRow: rt.DataStartRow()}
+	for _, row := range rt.validRows {
+		pr.Rows = append(pr.Rows, &ImportRow[*T]{RowNum: row.RowNum, Data: row.Data.(*T)})
+	}
+	if cellErrors := rt.CellErrors(); len(cellErrors) != 0 {
+		pr.Errors = importError(ctx, cellErrors, bindOpts).withHeaderRow(p.sheetName, p.headerRow)
+	}
+
+	return pr, nil
+}
+
+func BindExcelEach[T any](ctx *dgctx.DgContext, filePath string, opts *BindOptions, fn func(row *T, rowNum int) error) error {
+	return withExcelFile(ctx, filePath, func(file *os.File) error {
+		return BindExcelReaderEach[T](ctx, file, opts, fn)
+	})
+}
+
+func BindExcelReaderEach[T any](ctx *dgctx.DgContext, reader io.Reader, opts *BindOptions, fn func(row *T, rowNum int) error) error {
+	t := new(T)
+	p, err := newParser(t)
+	if err != nil {
+		dglogger.Errorf(ctx, "new parser error: %v", err)
+		return err
+	}
+
+	opts = contextBindOptions(ctx, opts)
+	var cellErrors []*CellError
+	err = p.ParseEach(reader, opts, func(body any, rowNum int, errList []*CellError) error {
+		if len(errList) != 0 {
+			cellErrors = append(cellErrors, errList...)
+			return nil
 		}
-		return nil, dgerr.SimpleDgError(strings.Join(errs, "\n"))
+		return fn(body.(*T), rowNum)
+	})
+	if err != nil {
+		dglogger.Errorf(ctx, "parse content error: %v", err)
+		return err
+	}
+	logParsedHeader(ctx, p, opts)
+	if len(cellErrors) != 0 {
+		return importError(ctx, cellErrors, opts).withHeaderRow(p.sheetName, p.headerRow)
 	}
 
-	ts := make([]*T, 0)
-	err = rt.Format(&ts)
+	return nil
+}
+
+func BindExcelBatch[T any](ctx *dgctx.DgContext, filePath string, opts *BindOptions, batchSize int, fn func(rows []*T) error) error {
+	return withExcelFile(ctx, filePath, func(file *os.File) error {
+		return BindExcelReaderBatch[T](ctx, file, opts, batchSize, fn)
+	})
+}
+
+func BindExcelReaderBatch[T any](ctx *dgctx.DgContext, reader io.Reader, opts *BindOptions, batchSize int, fn func(rows []*T) error) error {
+	if batchSize <= 0 {
+		return dgerr.ARGUMENT_NOT_VALID
+	}
+
+	batch := make([]*T, 0, batchSize)
+	err := BindExcelReaderEach[T](ctx, reader, opts, func(row *T, rowNum int) error {
+		batch = append(batch, row)
+		if len(batch) < batchSize {
+			return nil
+		}
+		err := fn(batch)
+		batch = make([]*T, 0, batchSize)
+		return err
+	})
 	if err != nil {
-		dglogger.Errorf(ctx, "bind to struct error: %v", err)
-		return nil, err
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
 	}
 
-	return ts, nil
+	return nil
 }
 
 func ExportStruct2XlsxFile(ctx *dgctx.DgContext, v any, filePath string) error {
@@ -119,3 +230,40 @@ func ExportStruct2XlsxFile(ctx *dgctx.DgContext, v any, filePath string) error {
 
 	return nil
 }
+
+func withExcelFile(ctx *dgctx.DgContext, filePath string, fn func(file *os.File) error) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		dglogger.Errorf(ctx, "open excel file error: %v", err)
+		return err
+	}
+	defer func(f *os.File) {
+		err := f.Close()
+		if err != nil {
+			dglogger.Errorf(ctx, "close excel file error: %v", err)
+		}
+	}(file)
+
+	return fn(file)
+}
+
+// logParsedHeader 记录自动识别的表头位置以及缺失、重复的列
+func logParsedHeader(ctx *dgctx.DgContext, p *parser, opts *BindOptions) {
+	if opts.AutoDetectHeader {
+		dglogger.Infof(ctx, "detected excel header row: %d, data start row: %d", p.headerRow, p.dataStartRow)
+	}
+	if len(p.missingColumns) != 0 {
+		dglogger.Warnf(ctx, "excel sheet[%s] missing columns: %v", p.sheetName, p.missingColumns)
+	}
+	if len(p.duplicateColumns) != 0 {
+		dglogger.Warnf(ctx, "excel sheet[%s] duplicate columns: %v", p.sheetName, p.duplicateColumns)
+	}
+}
+
+func importError(ctx *dgctx.DgContext, cellErrors []*CellError, opts *BindOptions) *ImportError {
+	ie := newImportError(cellErrors, opts.messageCatalog())
+	for _, ce := range ie.CellErrors {
+		dglogger.Warn(ctx, ce.Row, ce.Message)
+	}
+	return ie
+}
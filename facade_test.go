@@ -1,11 +1,15 @@
 package dgexcel
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	dgctx "github.com/darwinOrg/go-common/context"
 	dglogger "github.com/darwinOrg/go-logger"
 	"github.com/xuri/excelize/v2"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -35,6 +39,126 @@ func TestBindExcelOfPrototype(t *testing.T) {
 	dglogger.Infof(ctx, "%s", string(usersBytes))
 }
 
+func TestBindExcelBytes2Struct(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data, err := os.ReadFile("./users.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := BindExcelBytes2Struct[User](ctx, data, 1, 2)
+	if err != nil {
+		dglogger.Errorf(ctx, "bind excel bytes to struct error: \n%v", err)
+	}
+	dglogger.Infof(ctx, "users count: %d", len(users))
+
+	_, err = BindExcelBytes2Struct[User](ctx, []byte("name,status"), 1, 2)
+	if err == nil {
+		t.Fatal("expected format error")
+	}
+}
+
+// workbookBytes 将每个元素依次写入默认工作表的一行，nil 为空行
+func workbookBytes(t *testing.T, rows ...[]any) []byte {
+	xlsx := excelize.NewFile()
+	for i, row := range rows {
+		WriteRowDatas(xlsx, DefaultSheetName, i, 0, 0, row...)
+	}
+	buf, err := xlsx.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func employeeRows(count int) [][]any {
+	rows := [][]any{{"姓名", "手机号码"}}
+	for i := 1; i <= count; i++ {
+		rows = append(rows, []any{fmt.Sprintf("员工%d", i), fmt.Sprintf("1380000000%d", i)})
+	}
+	return rows
+}
+
+func TestBindExcelEach(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t, employeeRows(5)...)
+
+	var rowNums []int
+	err := BindExcelReaderEach[Employee](ctx, bytes.NewReader(data), nil, func(employee *Employee, rowNum int) error {
+		if employee.Name != fmt.Sprintf("员工%d", rowNum-1) {
+			t.Fatalf("unexpected employee at row %d: %+v", rowNum, employee)
+		}
+		rowNums = append(rowNums, rowNum)
+		return nil
+	})
+	if err != nil || len(rowNums) != 5 || rowNums[0] != 2 || rowNums[4] != 6 {
+		t.Fatalf("unexpected rows: %v, %v", rowNums, err)
+	}
+
+	//回调返回错误时立即停止
+	stopErr := errors.New("stop")
+	calls := 0
+	err = BindExcelReaderEach[Employee](ctx, bytes.NewReader(data), nil, func(employee *Employee, rowNum int) error {
+		calls++
+		if rowNum == 3 {
+			return stopErr
+		}
+		return nil
+	})
+	if !errors.Is(err, stopErr) || calls != 2 {
+		t.Fatalf("unexpected stop: %d, %v", calls, err)
+	}
+
+	//逐行读取时 MaxRow 为0不限制行数
+	AllowMaxRow = 3
+	defer func() { AllowMaxRow = 10000 }()
+	calls = 0
+	err = BindExcelReaderEach[Employee](ctx, bytes.NewReader(data), &BindOptions{MaxRow: 0}, func(employee *Employee, rowNum int) error {
+		calls++
+		return nil
+	})
+	if err != nil || calls != 5 {
+		t.Fatalf("unexpected unlimited rows: %d, %v", calls, err)
+	}
+	if _, err = BindExcelBytes2Struct[Employee](ctx, data, 1, 2, &BindOptions{MaxRow: -1}); err != nil {
+		t.Fatalf("unexpected negative max row error: %v", err)
+	}
+	if _, err = BindExcelBytes2Struct[Employee](ctx, data, 1, 2); err == nil {
+		t.Fatal("expected data overrun")
+	}
+}
+
+func TestBindExcelBatch(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t, employeeRows(5)...)
+
+	var batches [][]string
+	err := BindExcelReaderBatch[Employee](ctx, bytes.NewReader(data), nil, 2, func(employees []*Employee) error {
+		var names []string
+		for _, employee := range employees {
+			names = append(names, employee.Name)
+		}
+		batches = append(batches, names)
+		return nil
+	})
+	if err != nil || len(batches) != 3 || strings.Join(batches[0], ",") != "员工1,员工2" || strings.Join(batches[2], ",") != "员工5" {
+		t.Fatalf("unexpected batches: %v, %v", batches, err)
+	}
+
+	stopErr := errors.New("stop")
+	calls := 0
+	err = BindExcelReaderBatch[Employee](ctx, bytes.NewReader(data), nil, 2, func(employees []*Employee) error {
+		calls++
+		return stopErr
+	})
+	if !errors.Is(err, stopErr) || calls != 1 {
+		t.Fatalf("unexpected stop: %d, %v", calls, err)
+	}
+
+	if err = BindExcelReaderBatch[Employee](ctx, bytes.NewReader(data), nil, 0, func(employees []*Employee) error { return nil }); err == nil {
+		t.Fatal("expected invalid batch size error")
+	}
+}
+
 func TestSimpleExportStruct2XlsxFile(t *testing.T) {
 	ctx := &dgctx.DgContext{TraceId: "123"}
 	users, _ := SimpleBindExcel2Struct[User](ctx, "./users.xlsx")
@@ -168,3 +292,447 @@ func TestExportExcelSheets(t *testing.T) {
 		fmt.Println(err)
 	}
 }
+
+func TestBindExcel2StructBySheet(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	xlsx := excelize.NewFile()
+	_, _ = xlsx.NewSheet("员工")
+	_, _ = xlsx.NewSheet("离职员工")
+	WriteRowDatas(xlsx, "员工", 0, 0, 0, "姓名", "手机号码")
+	WriteRowDatas(xlsx, "员工", 1, 0, 0, "张三", "13800000000")
+	WriteRowDatas(xlsx, "离职员工", 0, 0, 0, "姓名", "手机号码")
+	WriteRowDatas(xlsx, "离职员工", 1, 0, 0, "李四", "13800000001")
+	WriteRowDatas(xlsx, "离职员工", 2, 0, 0, "王五", "13800000002")
+	buf, err := xlsx.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	//默认读取第一个工作表，该表为空
+	if _, err = BindExcelBytes2Struct[Employee](ctx, data, 1, 2); err == nil {
+		t.Fatal("expected empty first sheet error")
+	}
+
+	employees, err := BindExcelBytes2Struct[Employee](ctx, data, 1, 2, &BindOptions{FirstNonEmptySheet: true})
+	if err != nil || len(employees) != 1 || employees[0].Name != "张三" {
+		t.Fatalf("unexpected first non-empty sheet: %v, %v", employees, err)
+	}
+
+	employees, err = BindExcelBytes2Struct[Employee](ctx, data, 1, 2, &BindOptions{SheetIndex: 2})
+	if err != nil || len(employees) != 2 || employees[1].Name != "王五" {
+		t.Fatalf("unexpected sheet by index: %v, %v", employees, err)
+	}
+
+	if _, err = BindExcelBytes2Struct[Employee](ctx, data, 1, 2, &BindOptions{SheetIndex: 3}); err == nil {
+		t.Fatal("expected sheet index out of range error")
+	}
+
+	_, err = BindExcelBytes2Struct[Employee](ctx, data, 1, 2, &BindOptions{SheetName: "不存在"})
+	if err == nil {
+		t.Fatal("expected sheet not found error")
+	}
+	dglogger.Infof(ctx, "%v", err)
+}
+
+func TestBindExcelCellErrors(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "姓名"}, {Name: "状态"}, {Name: "创建日期"}},
+		Datas:   [][]any{{"张三", "有效", "03-11-24"}, {"李四", "未知", "04-12-24"}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = BindExcelBytes2Struct[User](ctx, buf.Bytes(), 1, 2)
+	var cellErr *CellError
+	if !errors.As(err, &cellErr) {
+		t.Fatalf("expected cell error, got: %v", err)
+	}
+	if cellErr.Row != 3 || cellErr.Column != "B" || cellErr.Code != CellErrorMapping || cellErr.Value != "未知" {
+		t.Fatalf("unexpected cell error: %+v", cellErr)
+	}
+}
+
+func TestBindExcelErrorLocale(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	SetLocale(ctx, LocaleEnUS)
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "姓名"}, {Name: "状态"}},
+		Datas:   [][]any{{"张三", "未知"}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = BindExcelBytes2Struct[User](ctx, buf.Bytes(), 1, 2)
+	if err == nil || err.Error() != "Row 2: 状态 has an invalid value" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = BindExcelBytes2Struct[User](ctx, buf.Bytes(), 1, 2, &BindOptions{
+		Locale:   LocaleZhCN,
+		Messages: Messages{MsgMapping: "{header}只能填写有效或无效"},
+	})
+	if err == nil || err.Error() != "第2行：状态只能填写有效或无效" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+type Employee struct {
+	Name  string `excel:"name(姓名|Name)"`
+	Phone string `excel:"name(手机号码|手机号|Phone)"`
+}
+
+func TestHeaderAliases(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "ＮＡＭＥ"}, {Name: "手机\n号"}},
+		Datas:   [][]any{{"张三", "13800000000"}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	employees, err := BindExcelBytes2Struct[Employee](ctx, buf.Bytes(), 1, 2)
+	if err != nil {
+		t.Fatalf("bind employees error: %v", err)
+	}
+	if employees[0].Name != "张三" || employees[0].Phone != "13800000000" {
+		t.Fatalf("unexpected employee: %+v", employees[0])
+	}
+	if aliases := HeaderAliases("name(金额(元)|Amount);required"); strings.Join(aliases, ",") != "金额(元),Amount" {
+		t.Fatalf("unexpected aliases: %v", aliases)
+	}
+}
+
+func TestAutoDetectHeader(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t,
+		[]any{"员工通讯录"},
+		[]any{"填写说明：手机号须为11位"},
+		nil,
+		[]any{"姓名", "手机号码"},
+		[]any{"张三", "13800000000"},
+	)
+
+	employees, err := BindExcelBytes2Struct[Employee](ctx, data, 0, 0, &BindOptions{AutoDetectHeader: true})
+	if err != nil {
+		t.Fatalf("bind employees error: %v", err)
+	}
+	if len(employees) != 1 || employees[0].Name != "张三" || employees[0].Phone != "13800000000" {
+		t.Fatalf("unexpected employees: %v", employees)
+	}
+
+	p, _ := newParser(new(Employee))
+	rt, err := p.ParseContent(bytes.NewReader(data), &BindOptions{AutoDetectHeader: true})
+	if err != nil {
+		t.Fatalf("parse content error: %v", err)
+	}
+	if rt.HeaderRow() != 4 || rt.DataStartRow() != 5 || len(rt.List()) != 1 {
+		t.Fatalf("unexpected header row: %d, data start row: %d", rt.HeaderRow(), rt.DataStartRow())
+	}
+	dglogger.Infof(ctx, "%+v", rt.List()[0])
+
+	pr, err := BindExcelReader2StructPartial[Employee](ctx, bytes.NewReader(data), 0, 0, &BindOptions{AutoDetectHeader: true})
+	if err != nil {
+		t.Fatalf("bind partial error: %v", err)
+	}
+	if pr.HeaderRow != 4 || pr.DataStartRow != 5 || len(pr.Rows) != 1 || pr.Rows[0].RowNum != 5 {
+		t.Fatalf("unexpected partial result: %d, %d, %v", pr.HeaderRow, pr.DataStartRow, pr.Rows)
+	}
+}
+
+type SupplierContact struct {
+	Supplier       string `excel:"name(供应商)"`
+	ContactName    string `excel:"name(联系人/姓名)"`
+	ContactPhone   string `excel:"name(联系人/电话)"`
+	EmergencyPhone string `excel:"name(紧急联系人/电话)"`
+}
+
+func TestMultiRowHeader(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	xlsx := excelize.NewFile()
+	WriteAndMergeCell(xlsx, DefaultSheetName, "A1", "A2", 0, "供应商")
+	WriteAndMergeCell(xlsx, DefaultSheetName, "B1", "C1", 0, "联系人")
+	WriteAndMergeCell(xlsx, DefaultSheetName, "D1", "D1", 0, "紧急联系人")
+	WriteRowDatas(xlsx, DefaultSheetName, 1, 1, 0, "姓名", "电话", "电话")
+	WriteRowDatas(xlsx, DefaultSheetName, 2, 0, 0, "甲公司", "张三", "13800000000", "13900000000")
+	buf, err := xlsx.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contacts, err := BindExcelBytes2Struct[SupplierContact](ctx, buf.Bytes(), 1, 3, &BindOptions{HeaderRows: 2})
+	if err != nil {
+		t.Fatalf("bind contacts error: %v", err)
+	}
+	c := contacts[0]
+	if c.Supplier != "甲公司" || c.ContactName != "张三" || c.ContactPhone != "13800000000" || c.EmergencyPhone != "13900000000" {
+		t.Fatalf("unexpected contact: %+v", c)
+	}
+}
+
+type DeptEmployee struct {
+	Dept string `excel:"name(部门);required"`
+	Name string `excel:"name(姓名)"`
+}
+
+func TestFillMergedCells(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	xlsx := excelize.NewFile()
+	WriteRowDatas(xlsx, DefaultSheetName, 0, 0, 0, "部门", "姓名")
+	WriteAndMergeCell(xlsx, DefaultSheetName, "A2", "A4", 0, "研发部")
+	WriteColumnDatas(xlsx, DefaultSheetName, 1, 2, 0, "张三", "李四", "王五")
+	buf, err := xlsx.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	employees, err := BindExcelBytes2Struct[DeptEmployee](ctx, buf.Bytes(), 1, 2, &BindOptions{FillMergedCells: true})
+	if err != nil {
+		t.Fatalf("bind employees error: %v", err)
+	}
+	if len(employees) != 3 || employees[2].Dept != "研发部" {
+		t.Fatalf("unexpected employees: %d", len(employees))
+	}
+}
+
+type PartnerOrder struct {
+	OrderNo string  `excel:"col(A)"`
+	Amount  float64 `excel:"name(金额);index(2)"`
+	Buyer   string  `excel:"name(买家)"`
+}
+
+func TestBindFixedColumns(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t,
+		[]any{"P001", "忽略", "12.5"},
+		[]any{"P002", "忽略", "abc"},
+	)
+
+	_, err := BindExcelBytes2Struct[PartnerOrder](ctx, data, 0, 1)
+	var cellErr *CellError
+	if !errors.As(err, &cellErr) || cellErr.Row != 2 || cellErr.Column != "C" || cellErr.Header != "金额" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var orders []*PartnerOrder
+	err = BindExcelReaderEach[PartnerOrder](ctx, bytes.NewReader(data), &BindOptions{NoHeader: true, MaxRow: 1}, func(order *PartnerOrder, rowNum int) error {
+		orders = append(orders, order)
+		return nil
+	})
+	if err == nil || len(orders) != 1 || orders[0].OrderNo != "P001" || orders[0].Amount != 12.5 {
+		t.Fatalf("unexpected orders: %v, %v", orders, err)
+	}
+}
+
+type Applicant struct {
+	Name   string `excel:"name(姓名);required"`
+	Phone  string `excel:"name(手机号)"`
+	Remark string `excel:"name(备注)"`
+}
+
+func TestValidateHeader(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	row := []any{"张三", "13800000000", "无"}
+	data := workbookBytes(t, []any{"姓名", "手机号", "年龄"}, row)
+	rt, err := RegisterSheet[Applicant](NewMultiSheetBinder(), DefaultSheetName).BindReader(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing, unknown := rt.Sheet(DefaultSheetName).MissingColumns(), rt.Sheet(DefaultSheetName).UnknownColumns(); len(missing) != 1 || missing[0] != "备注" || len(unknown) != 1 || unknown[0] != "年龄" {
+		t.Fatalf("unexpected columns: %v, %v", missing, unknown)
+	}
+
+	var headerErr *HeaderError
+	_, err = BindExcelBytes2Struct[Applicant](ctx, data, 1, 2, &BindOptions{StrictHeader: true, RequireAllColumns: true})
+	if !errors.As(err, &headerErr) || len(headerErr.Missing) != 1 || len(headerErr.Unknown) != 1 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = BindExcelBytes2Struct[Applicant](ctx, workbookBytes(t, []any{"手机号", "备注"}, row), 1, 2)
+	if !errors.As(err, &headerErr) || len(headerErr.Missing) != 1 || headerErr.Missing[0] != "姓名" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data = workbookBytes(t, []any{"姓名", "手机号", "手机号"}, row)
+	rt, err = RegisterSheet[Applicant](NewMultiSheetBinder(), DefaultSheetName).BindReader(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if duplicates := rt.Sheet(DefaultSheetName).DuplicateColumns(); len(duplicates) != 1 || duplicates[0] != "手机号" {
+		t.Fatalf("unexpected duplicate columns: %v", duplicates)
+	}
+
+	_, err = BindExcelBytes2Struct[Applicant](ctx, data, 1, 2, &BindOptions{StrictHeader: true})
+	if !errors.As(err, &headerErr) || len(headerErr.Duplicates) != 1 || headerErr.Duplicates[0] != "手机号" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+type Subscriber struct {
+	Name  string `excel:"name(姓名)"`
+	Phone string `excel:"name(手机号);unique"`
+}
+
+func TestDuplicateColumnsUnique(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t,
+		[]any{"姓名", "手机号", "手机号"},
+		[]any{"张三", "13800000001", "13800000001"},
+		[]any{"李四", "13800000002", "13800000001"},
+	)
+
+	subscribers, err := BindExcelBytes2Struct[Subscriber](ctx, data, 1, 2)
+	if err != nil {
+		t.Fatalf("bind subscribers error: %v", err)
+	}
+	if len(subscribers) != 2 || subscribers[0].Phone != "13800000001" || subscribers[1].Phone != "13800000002" {
+		t.Fatalf("unexpected subscribers: %+v, %+v", subscribers[0], subscribers[1])
+	}
+}
+
+type Customer struct {
+	Name  string            `excel:"name(姓名)"`
+	Extra map[string]string `excel:"extra"`
+}
+
+func TestExtraColumns(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t,
+		[]any{"姓名", "备注2", "等级"},
+		[]any{"张三", "老客户", "A"},
+	)
+
+	customers, err := BindExcelBytes2Struct[Customer](ctx, data, 1, 2, &BindOptions{StrictHeader: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(customers) != 1 || customers[0].Extra["备注2"] != "老客户" || customers[0].Extra["等级"] != "A" {
+		t.Fatalf("unexpected customers: %v", customers)
+	}
+
+	//原型中已初始化的 extra map 不能在各行之间共用
+	data = workbookBytes(t,
+		[]any{"姓名", "等级"},
+		[]any{"张三", "A"},
+		[]any{"李四", "B"},
+	)
+	results, err := BindExcelReaderUsingTargetBuilder(ctx, bytes.NewReader(data), 1, 2, func() any {
+		return &Customer{Extra: map[string]string{}}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].(*Customer).Extra["等级"] != "A" || results[1].(*Customer).Extra["等级"] != "B" {
+		t.Fatalf("unexpected results: %v", results)
+	}
+
+	exported, err := ExportStruct2Xlsx(customers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := exported.GetRows(DefaultSheetName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || strings.Join(rows[0], ",") != "姓名,备注2,等级" || strings.Join(rows[1], ",") != "张三,老客户,A" {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+}
+
+func TestBindExcel2StructPartial(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t,
+		[]any{"姓名", "手机号"},
+		[]any{"张三", "13800000000"},
+		[]any{"", "13800000001"},
+		[]any{"李四", "13800000002"},
+	)
+
+	pr, err := BindExcelReader2StructPartial[Applicant](ctx, bytes.NewReader(data), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr.Rows) != 2 || pr.Rows[0].RowNum != 2 || pr.Rows[1].RowNum != 4 || pr.Rows[1].Data.Name != "李四" {
+		t.Fatalf("unexpected rows: %v", pr.Rows)
+	}
+	if pr.Errors == nil || len(pr.Errors.CellErrors) != 1 || pr.Errors.CellErrors[0].Row != 3 {
+		t.Fatalf("unexpected errors: %v", pr.Errors)
+	}
+}
+
+type Setting struct {
+	Name    string `excel:"name(名称)"`
+	Enabled bool   `excel:"name(启用)"`
+	Limit   *int   `excel:"name(上限)"`
+	Level   int    `excel:"name(等级);default(3)"`
+	Status  int    `excel:"name(状态);mapping(无效:0,有效:1);default(有效)"`
+	Owner   string `excel:"name(负责人);default(admin)"`
+}
+
+func TestDefaultAndEmptyValues(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t,
+		[]any{"名称", "启用", "上限", "等级", "状态"},
+		[]any{"a", "", "", "", ""},
+		[]any{"b", "true", "10", "5", "无效"},
+	)
+
+	settings, err := BindExcelBytes2Struct[Setting](ctx, data, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(settings) != 2 {
+		t.Fatalf("unexpected settings: %v", settings)
+	}
+	empty, filled := settings[0], settings[1]
+	if empty.Enabled || empty.Limit != nil || empty.Level != 3 || empty.Status != 1 || empty.Owner != "admin" {
+		t.Fatalf("unexpected empty row: %+v", empty)
+	}
+	if !filled.Enabled || filled.Limit == nil || *filled.Limit != 10 || filled.Level != 5 || filled.Status != 0 || filled.Owner != "admin" {
+		t.Fatalf("unexpected filled row: %+v", filled)
+	}
+}
+
+type MappedStatus struct {
+	Name   string `excel:"name(名称)"`
+	Status int    `excel:"name(状态);mapping(无效:0,有效:1)"`
+	Level  *int   `excel:"name(等级);mapping(低:1,高:2)"`
+}
+
+func TestBlankMappedCells(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t,
+		[]any{"名称", "状态", "等级"},
+		[]any{"a", "有效", ""},
+		[]any{"b", "", "高"},
+	)
+
+	pr, err := BindExcelReader2StructPartial[MappedStatus](ctx, bytes.NewReader(data), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr.Rows) != 1 || pr.Rows[0].Data.Status != 1 || pr.Rows[0].Data.Level != nil {
+		t.Fatalf("unexpected rows: %v", pr.Rows)
+	}
+	if pr.Errors == nil || len(pr.Errors.CellErrors) != 1 || pr.Errors.CellErrors[0].Row != 3 || pr.Errors.CellErrors[0].Code != CellErrorMapping {
+		t.Fatalf("unexpected errors: %v", pr.Errors)
+	}
+
+	//行尾的空单元格不会被读取，与中间的空单元格一致
+	data = workbookBytes(t,
+		[]any{"名称", "状态"},
+		[]any{"a", "有效"},
+		[]any{"b"},
+	)
+	pr, err = BindExcelReader2StructPartial[MappedStatus](ctx, bytes.NewReader(data), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr.Rows) != 1 || pr.Errors == nil || len(pr.Errors.CellErrors) != 1 || pr.Errors.CellErrors[0].Row != 3 || pr.Errors.CellErrors[0].Code != CellErrorMapping {
+		t.Fatalf("unexpected trailing blank result: %v, %v", pr.Rows, pr.Errors)
+	}
+}
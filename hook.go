@@ -0,0 +1,49 @@
+package dgexcel
+
+import (
+	"errors"
+)
+
+// ExcelRowValidator 跨字段校验，在一行绑定完成且没有单元格错误时调用，返回的错误合并到该行的错误中
+// 返回 *CellError 时可指定 Column、Header 定位到具体单元格，返回的错误会被复制，可以复用同一个错误值
+type ExcelRowValidator interface {
+	ValidateExcelRow() []error
+}
+
+// ExcelAfterBind 一行绑定且校验通过后调用，可用于计算派生字段，返回错误同样计入该行
+type ExcelAfterBind interface {
+	AfterExcelBind() error
+}
+
+// rowHooks 调用目标结构体的行级钩子，存在单元格错误时结构体只绑定了一部分，不再调用钩子
+func (p *parser) rowHooks(body any, rowNum int, errList []*CellError) []*CellError {
+	if len(errList) != 0 {
+		return errList
+	}
+	var hookErrs []error
+	if validator, ok := body.(ExcelRowValidator); ok {
+		hookErrs = append(hookErrs, validator.ValidateExcelRow()...)
+	}
+	if afterBind, ok := body.(ExcelAfterBind); ok && len(hookErrs) == 0 {
+		hookErrs = append(hookErrs, afterBind.AfterExcelBind())
+	}
+	for _, err := range hookErrs {
+		if err == nil {
+			continue
+		}
+		var ce *CellError
+		if errors.As(err, &ce) {
+			c := *ce
+			ce = &c
+		} else {
+			ce = newCellError(CellErrorRow, err.Error())
+		}
+		if ce.Code == "" {
+			ce.Code = CellErrorRow
+		}
+		ce.Sheet = p.sheetName
+		ce.Row = rowNum
+		errList = append(errList, ce)
+	}
+	return errList
+}
@@ -0,0 +1,56 @@
+package dgexcel
+
+import (
+	"bytes"
+	"errors"
+	dgctx "github.com/darwinOrg/go-common/context"
+	"testing"
+)
+
+type Promotion struct {
+	Name      string `excel:"name(名称)"`
+	StartDate string `excel:"name(开始日期)"`
+	EndDate   string `excel:"name(结束日期)"`
+	Days      string
+}
+
+var errPromotionEndDate = &CellError{Column: "C", Header: "结束日期", Message: "结束日期不能早于开始日期"}
+
+func (p *Promotion) ValidateExcelRow() []error {
+	if p.EndDate < p.StartDate {
+		return []error{errPromotionEndDate}
+	}
+	return nil
+}
+
+func (p *Promotion) AfterExcelBind() error {
+	if p.Name == "" {
+		return errors.New("名称为空")
+	}
+	p.Days = p.StartDate + "~" + p.EndDate
+	return nil
+}
+
+func TestRowHooks(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	data := workbookBytes(t,
+		[]any{"名称", "开始日期", "结束日期"},
+		[]any{"双十一", "2024-11-01", "2024-11-11"},
+		[]any{"双十二", "2024-12-12", "2024-12-01"},
+		[]any{"", "2024-12-01", "2024-12-12"},
+		[]any{"元旦", "2025-01-03", "2025-01-01"},
+	)
+
+	pr, err := BindExcelReader2StructPartial[Promotion](ctx, bytes.NewReader(data), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr.Rows) != 1 || pr.Rows[0].Data.Days != "2024-11-01~2024-11-11" {
+		t.Fatalf("unexpected rows: %v", pr.Rows)
+	}
+	cellErrors := pr.Errors.CellErrors
+	if len(cellErrors) != 3 || cellErrors[0].Row != 3 || cellErrors[0].Column != "C" || cellErrors[0].Code != CellErrorRow ||
+		cellErrors[1].Row != 4 || cellErrors[1].Message != "名称为空" || cellErrors[2].Row != 5 || errPromotionEndDate.Row != 0 {
+		t.Fatalf("unexpected errors: %v", cellErrors)
+	}
+}
@@ -0,0 +1,178 @@
+package dgexcel
+
+import (
+	dgctx "github.com/darwinOrg/go-common/context"
+	"strings"
+	"sync"
+)
+
+const (
+	LocaleZhCN = "zh-CN"
+	LocaleEnUS = "en-US"
+)
+
+// 消息键，消息模板中可使用 {header} {value} {row} {sheet} {message} 等占位符
+const (
+	MsgUnique          = "unique"
+	MsgDate            = "date"
+	MsgMapping         = "mapping"
+	MsgTypeBool        = "type.bool"
+	MsgTypeInt         = "type.int"
+	MsgTypeFloat       = "type.float"
+	MsgTypeConvert     = "type.convert"
+	MsgRequired        = "required"
+	MsgMin             = "min"
+	MsgMax             = "max"
+	MsgLen             = "len"
+	MsgRegex           = "regex"
+	MsgEmail           = "email"
+	MsgOneOf           = "oneof"
+	MsgValidate        = "validate"
+	MsgHeaderMissing   = "header.missing"
+	MsgHeaderUnknown   = "header.unknown"
+	MsgHeaderDuplicate = "header.duplicate"
+	MsgErrorColumn     = "error.column"
+	MsgTplRequired     = "template.required"
+	MsgTplUnique       = "template.unique"
+	MsgTplDate         = "template.date"
+	MsgTplDefault      = "template.default"
+	MsgTplInstructions = "template.instructions"
+	MsgRowPrefix       = "row"
+	MsgSheetPrefix     = "sheet"
+)
+
+const localeCtxKey = "dgexcel.locale"
+
+var DefaultLocale = LocaleZhCN
+
+type Messages map[string]string
+
+var (
+	messageBundles = map[string]Messages{
+		LocaleZhCN: {
+			MsgUnique:          "{header}[{value}]不可重复，与第{conflictRow}行重复",
+			MsgDate:            "{header}单元格格式错误",
+			MsgMapping:         "{header}单元格存在非法输入",
+			MsgTypeBool:        "{header}单元格非法输入,参数非bool类型值",
+			MsgTypeInt:         "{header}单元格非法输入,参数非整形数值",
+			MsgTypeFloat:       "{header}单元格非法输入,参数非浮点型数值",
+			MsgTypeConvert:     "{header}单元格非法输入,无法转换为{type}",
+			MsgRequired:        "{header}不能为空",
+			MsgMin:             "{header}不能小于{min}",
+			MsgMax:             "{header}不能大于{max}",
+			MsgLen:             "{header}长度须在{min}到{max}之间",
+			MsgRegex:           "{header}[{value}]格式不正确",
+			MsgEmail:           "{header}[{value}]不是有效的邮箱地址",
+			MsgOneOf:           "{header}只能是{options}之一",
+			MsgValidate:        "{header}[{value}]校验失败：{reason}",
+			MsgHeaderMissing:   "缺少列：{columns}",
+			MsgHeaderUnknown:   "存在无法识别的列：{columns}",
+			MsgHeaderDuplicate: "列重复：{columns}",
+			MsgErrorColumn:     "错误信息",
+			MsgTplRequired:     "必填",
+			MsgTplUnique:       "不可重复",
+			MsgTplDate:         "日期格式：{layout}",
+			MsgTplDefault:      "默认值：{value}",
+			MsgTplInstructions: "填写说明",
+			MsgRowPrefix:       "第{row}行：{message}",
+			MsgSheetPrefix:     "工作表[{sheet}] {message}",
+		},
+		LocaleEnUS: {
+			MsgUnique:          "{header} [{value}] must be unique, duplicates row {conflictRow}",
+			MsgDate:            "{header} has an invalid date format",
+			MsgMapping:         "{header} has an invalid value",
+			MsgTypeBool:        "{header} must be a boolean value",
+			MsgTypeInt:         "{header} must be an integer",
+			MsgTypeFloat:       "{header} must be a number",
+			MsgTypeConvert:     "{header} cannot be converted to {type}",
+			MsgRequired:        "{header} is required",
+			MsgMin:             "{header} must not be less than {min}",
+			MsgMax:             "{header} must not be greater than {max}",
+			MsgLen:             "{header} length must be between {min} and {max}",
+			MsgRegex:           "{header} [{value}] has an invalid format",
+			MsgEmail:           "{header} [{value}] is not a valid email address",
+			MsgOneOf:           "{header} must be one of {options}",
+			MsgValidate:        "{header} [{value}] is invalid: {reason}",
+			MsgHeaderMissing:   "Missing columns: {columns}",
+			MsgHeaderUnknown:   "Unknown columns: {columns}",
+			MsgHeaderDuplicate: "Duplicate columns: {columns}",
+			MsgErrorColumn:     "Errors",
+			MsgTplRequired:     "Required",
+			MsgTplUnique:       "Must be unique",
+			MsgTplDate:         "Date format: {layout}",
+			MsgTplDefault:      "Default: {value}",
+			MsgTplInstructions: "Instructions",
+			MsgRowPrefix:       "Row {row}: {message}",
+			MsgSheetPrefix:     "Sheet [{sheet}] {message}",
+		},
+	}
+	messageBundlesLock sync.RWMutex
+)
+
+// RegisterMessages 注册或覆盖某个语言的消息，可用于新增语言
+func RegisterMessages(locale string, messages Messages) {
+	messageBundlesLock.Lock()
+	defer messageBundlesLock.Unlock()
+
+	bundle, ok := messageBundles[locale]
+	if !ok {
+		bundle = make(Messages, len(messages))
+		messageBundles[locale] = bundle
+	}
+	for key, msg := range messages {
+		bundle[key] = msg
+	}
+}
+
+func SetLocale(ctx *dgctx.DgContext, locale string) {
+	ctx.SetExtraKeyValue(localeCtxKey, locale)
+}
+
+func GetLocale(ctx *dgctx.DgContext) string {
+	if ctx != nil {
+		if locale, ok := ctx.GetExtraValue(localeCtxKey).(string); ok && locale != "" {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+type messageCatalog struct {
+	locale    string
+	overrides Messages
+}
+
+func newMessageCatalog(locale string, overrides Messages) *messageCatalog {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	return &messageCatalog{locale: locale, overrides: overrides}
+}
+
+func (c *messageCatalog) render(key string, params map[string]string) string {
+	msg := c.lookup(key)
+	if len(params) == 0 {
+		return msg
+	}
+	oldnew := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		oldnew = append(oldnew, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(oldnew...).Replace(msg)
+}
+
+func (c *messageCatalog) lookup(key string) string {
+	if msg, ok := c.overrides[key]; ok {
+		return msg
+	}
+
+	messageBundlesLock.RLock()
+	defer messageBundlesLock.RUnlock()
+	if msg, ok := messageBundles[c.locale][key]; ok {
+		return msg
+	}
+	if msg, ok := messageBundles[DefaultLocale][key]; ok {
+		return msg
+	}
+	return messageBundles[LocaleZhCN][key]
+}
@@ -0,0 +1,130 @@
+package dgexcel
+
+import (
+	dgctx "github.com/darwinOrg/go-common/context"
+	dglogger "github.com/darwinOrg/go-logger"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"os"
+)
+
+type MultiSheetBinder struct {
+	sheets []*sheetBinder
+}
+
+type sheetBinder struct {
+	opts            *BindOptions
+	targetBuilderFn func() any
+}
+
+type MultiSheetResult struct {
+	sheetNames []string
+	results    map[string]*Result
+}
+
+func NewMultiSheetBinder() *MultiSheetBinder {
+	return &MultiSheetBinder{}
+}
+
+func RegisterSheet[T any](b *MultiSheetBinder, sheetName string, opts ...*BindOptions) *MultiSheetBinder {
+	return b.Register(sheetName, func() any { return new(T) }, opts...)
+}
+
+func (b *MultiSheetBinder) Register(sheetName string, targetBuilderFn func() any, opts ...*BindOptions) *MultiSheetBinder {
+	var o *BindOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	o = o.normalize()
+	o.SheetName = sheetName
+	b.sheets = append(b.sheets, &sheetBinder{opts: o, targetBuilderFn: targetBuilderFn})
+	return b
+}
+
+func (b *MultiSheetBinder) Bind(ctx *dgctx.DgContext, filePath string) (*MultiSheetResult, error) {
+	var msr *MultiSheetResult
+	err := withExcelFile(ctx, filePath, func(file *os.File) (err error) {
+		msr, err = b.BindReader(ctx, file)
+		return err
+	})
+	return msr, err
+}
+
+func (b *MultiSheetBinder) BindReader(ctx *dgctx.DgContext, reader io.Reader) (*MultiSheetResult, error) {
+	xlsx, err := openExcelReader(reader)
+	if err != nil {
+		dglogger.Errorf(ctx, "open excel reader error: %v", err)
+		return nil, err
+	}
+	defer func(xlsx *excelize.File) {
+		_ = xlsx.Close()
+	}(xlsx)
+
+	msr := &MultiSheetResult{results: make(map[string]*Result)}
+	var cellErrors []*CellError
+	sheetMessages := make(map[string]*messageCatalog)
+	for _, sheet := range b.sheets {
+		p, err := newParser(sheet.targetBuilderFn())
+		if err != nil {
+			dglogger.Errorf(ctx, "new parser error: %v", err)
+			return nil, err
+		}
+		p.file = xlsx
+
+		opts := contextBindOptions(ctx, sheet.opts)
+		rt, err := p.parseSheetContent(opts)
+		if err != nil {
+			dglogger.Errorf(ctx, "parse sheet[%s] content error: %v", sheet.opts.SheetName, err)
+			return nil, err
+		}
+		logParsedHeader(ctx, p, opts)
+		msr.sheetNames = append(msr.sheetNames, sheet.opts.SheetName)
+		msr.results[sheet.opts.SheetName] = rt
+		sheetMessages[p.sheetName] = p.messages
+
+		cellErrors = append(cellErrors, rt.CellErrors()...)
+	}
+	if len(cellErrors) > 0 {
+		ie := importError(ctx, cellErrors, contextBindOptions(ctx, nil))
+		ie.withSheet, ie.sheetMessages = true, sheetMessages
+		for _, sheetName := range msr.sheetNames {
+			ie.withHeaderRow(sheetName, msr.results[sheetName].HeaderRow())
+		}
+		return msr, ie
+	}
+
+	return msr, nil
+}
+
+func (r *MultiSheetResult) SheetNames() []string {
+	return r.sheetNames
+}
+
+func (r *MultiSheetResult) Sheet(sheetName string) *Result {
+	return r.results[sheetName]
+}
+
+func (r *MultiSheetResult) HasError() (map[string]map[int][]string, bool) {
+	errs := make(map[string]map[int][]string)
+	for sheetName, rt := range r.results {
+		if errList, has := rt.HasError(); has {
+			errs[sheetName] = errList
+		}
+	}
+	return errs, len(errs) != 0
+}
+
+func SheetList[T any](r *MultiSheetResult, sheetName string) []*T {
+	rt := r.Sheet(sheetName)
+	if rt == nil {
+		return nil
+	}
+
+	ts := make([]*T, 0, len(rt.mappingResults))
+	for _, elem := range rt.mappingResults {
+		if t, ok := elem.(*T); ok {
+			ts = append(ts, t)
+		}
+	}
+	return ts
+}
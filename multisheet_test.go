@@ -0,0 +1,85 @@
+package dgexcel
+
+import (
+	"bytes"
+	dgctx "github.com/darwinOrg/go-common/context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type Supplier struct {
+	Name string `excel:"name(供应商名称)"`
+	Code string `excel:"name(供应商编码);unique(true)"`
+}
+
+type Contact struct {
+	SupplierCode string `excel:"name(供应商编码)"`
+	Name         string `excel:"name(联系人)"`
+	Phone        string `excel:"name(电话)"`
+}
+
+func TestMultiSheetBinder(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	filePath := filepath.Join(t.TempDir(), "suppliers.xlsx")
+	xlsx := ExportExcelSheets([]*ExcelSheet{
+		{
+			Name:    "供应商",
+			Headers: []*ExcelHeader{{Name: "供应商名称"}, {Name: "供应商编码"}},
+			Datas:   [][]any{{"甲公司", "S001"}, {"乙公司", "S002"}},
+		},
+		{
+			Name:    "联系人",
+			Headers: []*ExcelHeader{{Name: "供应商编码"}, {Name: "联系人"}, {Name: "电话"}},
+			Datas:   [][]any{{"S001", "张三", "13800000000"}},
+		},
+	})
+	if err := xlsx.SaveAs(filePath); err != nil {
+		t.Fatal(err)
+	}
+
+	binder := NewMultiSheetBinder()
+	RegisterSheet[Supplier](binder, "供应商")
+	RegisterSheet[Contact](binder, "联系人")
+	rt, err := binder.Bind(ctx, filePath)
+	if err != nil {
+		t.Fatalf("bind multi sheets error: %v", err)
+	}
+
+	suppliers := SheetList[Supplier](rt, "供应商")
+	contacts := SheetList[Contact](rt, "联系人")
+	if len(suppliers) != 2 || len(contacts) != 1 || contacts[0].Phone != "13800000000" {
+		t.Fatalf("unexpected result: %d suppliers, %d contacts", len(suppliers), len(contacts))
+	}
+}
+
+func TestMultiSheetLocale(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{
+		{
+			Name:    "Suppliers",
+			Headers: []*ExcelHeader{{Name: "供应商名称"}, {Name: "供应商编码"}},
+			Datas:   [][]any{{"甲公司", "S001"}, {"乙公司", "S001"}},
+		},
+		{
+			Name:    "供应商",
+			Headers: []*ExcelHeader{{Name: "供应商名称"}, {Name: "供应商编码"}},
+			Datas:   [][]any{{"甲公司", "S002"}, {"乙公司", "S002"}},
+		},
+	}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binder := NewMultiSheetBinder()
+	RegisterSheet[Supplier](binder, "Suppliers", &BindOptions{Locale: LocaleEnUS})
+	RegisterSheet[Supplier](binder, "供应商")
+	_, err = binder.BindReader(ctx, bytes.NewReader(buf.Bytes()))
+	if err == nil {
+		t.Fatal("expected import error")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "Sheet [Suppliers] Row 3: ") || !strings.HasPrefix(lines[1], "工作表[供应商] 第3行：") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package dgexcel
+
+import (
+	dgctx "github.com/darwinOrg/go-common/context"
+	"time"
+)
+
+type BindOptions struct {
+	// 表头所在行，从1开始，默认为1
+	HeaderRow int
+	// 没有表头，仅按 col(C) 或 index(2) 绑定字段
+	NoHeader bool
+	// 数据开始行，从1开始，默认为表头下一行
+	DataStartRow int
+	// 表头占用的行数，默认为1，多行表头中合并的上级标题会填充到其覆盖的每一列，标签中以 name(联系人/电话) 指定
+	HeaderRows int
+	// 数据行数上限，小于0时不限制；为0时逐行读取不限制，一次性读取全部数据时取 AllowMaxRow
+	MaxRow int
+	// 按名称选择工作表，优先级最高
+	SheetName string
+	// 按索引选择工作表，从0开始
+	SheetIndex int
+	// 选择第一个非空工作表
+	FirstNonEmptySheet bool
+	// 错误消息语言，为空时取 context 中的语言
+	Locale string
+	// 覆盖内置的错误消息
+	Messages Messages
+	// 解析时间使用的时区，默认为 time.Local
+	TimeZone *time.Location
+	// 自动识别表头，在前 HeaderScanRows 行中选择匹配表头名称最多的行，数据从其下一行开始
+	AutoDetectHeader bool
+	// 自动识别表头时扫描的行数，默认为10
+	HeaderScanRows int
+	// 将数据区域中合并单元格的值填充到其覆盖的每一行
+	FillMergedCells bool
+	// 表头缺少任意字段对应的列时报错，默认只有标记 required 的字段缺列时报错
+	RequireAllColumns bool
+	// 严格模式，表头存在无法识别或重复的列时报错
+	StrictHeader bool
+}
+
+func (o *BindOptions) normalize() *BindOptions {
+	opts := &BindOptions{}
+	if o != nil {
+		*opts = *o
+	}
+	if opts.NoHeader {
+		opts.HeaderRow = 0
+	} else if opts.HeaderRow == 0 {
+		opts.HeaderRow = 1
+	}
+	if opts.HeaderRows <= 0 {
+		opts.HeaderRows = 1
+	}
+	if opts.DataStartRow == 0 {
+		opts.DataStartRow = opts.HeaderRow + opts.HeaderRows
+	}
+	if opts.HeaderScanRows == 0 {
+		opts.HeaderScanRows = 10
+	}
+	if opts.TimeZone == nil {
+		opts.TimeZone = time.Local
+	}
+	return opts
+}
+
+func mergeBindOptions(ctx *dgctx.DgContext, headerRow int, dataStartRow int, opts []*BindOptions) *BindOptions {
+	var o *BindOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	o = contextBindOptions(ctx, o)
+	o.HeaderRow = headerRow
+	o.DataStartRow = dataStartRow
+	//自动识别表头时位置可传0，由识别结果决定
+	o.NoHeader = headerRow == 0 && !o.AutoDetectHeader
+	return o
+}
+
+func contextBindOptions(ctx *dgctx.DgContext, opts *BindOptions) *BindOptions {
+	opts = opts.normalize()
+	if opts.Locale == "" {
+		opts.Locale = GetLocale(ctx)
+	}
+	return opts
+}
+
+func (o *BindOptions) messageCatalog() *messageCatalog {
+	return newMessageCatalog(o.Locale, o.Messages)
+}
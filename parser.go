@@ -1,29 +1,65 @@
 package dgexcel
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/xuri/excelize/v2"
-	"os"
-	"path"
+	"io"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 	"unsafe"
 )
 
+// AllowMaxRow 一次性读取全部数据时的行数上限，小于等于0时不限制
 var AllowMaxRow = 10000
 
+var zipSignature = []byte("PK\x03\x04")
+
+// extraType 收集未映射列的字段类型
+var extraType = reflect.TypeOf(map[string]string{})
+
 type parser struct {
 	file         *excelize.File
 	fieldMapping map[string]map[string]string
 	sheetName    string
 	body         any
 	val          reflect.Value
-	uniqueMap    map[int][]string
+	//unique 索引，键为字段或 unique(groupName) 的组名，值为键值到首次出现行号的映射
+	uniqueIndex  map[string]map[string]int
+	uniqueGroups map[string][]string
+	groupNames   []string
+	rowUnique    map[string]*uniquePart
+	messages     *messageCatalog
+	regexCache   map[string]*regexp.Regexp
+	structFields map[string]reflect.StructField
+	location     *time.Location
+	date1904     bool
+	headerRow    int
+	dataStartRow int
+	leafMapping  map[string]map[string]string
+	fixedColumns []*fixedColumn
+	//按表头绑定的字段，顺序与结构体一致
+	fieldNames       []string
+	extraField       string
+	fieldTitles      map[string]string
+	missingColumns   []string
+	unknownColumns   []string
+	duplicateColumns []string
+	defaultFields    []map[string]string
+	//表头每列对应的字段映射，未匹配的列为 nil
+	headerMapping []map[string]string
+	mergeCells    []excelize.MergeCell
+	mergeLoaded   bool
+	//存在日期字段时同步读取原始值，rawRow 为当前行未经数字格式化的值
+	rawValues bool
+	rawRow    []string
 }
 
 func newParser(body any) (*parser, error) {
@@ -35,8 +71,13 @@ func newParser(body any) (*parser, error) {
 	p.body = body
 
 	p.fieldMapping = make(map[string]map[string]string)
+	p.regexCache = make(map[string]*regexp.Regexp)
+	p.structFields = make(map[string]reflect.StructField)
+	p.fieldTitles = make(map[string]string)
+	p.uniqueGroups = make(map[string][]string)
 	//生成结构体与excel头映射关系
 	p.generateMapping(p.val, "")
+	p.generateLeafMapping()
 	return p, nil
 }
 
@@ -68,13 +109,128 @@ func (p *parser) generateMapping(val reflect.Value, baseField string) {
 			p.generateMapping(fieldVal, fieldName)
 			continue
 		}
+		tagOptions := parseTagOptions(excel)
+		p.structFields[fieldName] = typ.Field(i)
+		if isTimeField(typ.Field(i)) || tagOptions[dateTag] != "" {
+			p.rawValues = true
+		}
+		//excel:"extra" 收集未映射的列
+		if _, ok := tagOptions[extraTag]; ok && typ.Field(i).Type == extraType {
+			p.extraField = fieldName
+			continue
+		}
 		m := map[string]string{nameTag: fieldName}
-		m[mappingTag], _ = stringMatchExport(excel, regexp.MustCompile(`mapping\((.*?)\)`))
-		m[uniqueTag], _ = stringMatchExport(excel, regexp.MustCompile(`unique\((.*?)\)`))
-		m[dateTag], _ = stringMatchExport(excel, regexp.MustCompile(`date\((.*?)\)`))
-		mappingName, _ := stringMatchExport(excel, regexp.MustCompile(`name\((.*?)\)`))
-		p.fieldMapping[strings.TrimSpace(mappingName)] = m
+		for key, value := range tagOptions {
+			if key != nameTag {
+				m[key] = value
+			}
+		}
+		//unique(groupName) 多个字段组成联合唯一键
+		if group, _ := uniqueGroup(tagOptions[uniqueTag]); group != "" {
+			if _, ok := p.uniqueGroups[group]; !ok {
+				p.groupNames = append(p.groupNames, group)
+			}
+			p.uniqueGroups[group] = append(p.uniqueGroups[group], fieldName)
+		}
+		//col(C) 或 index(2) 按固定列绑定，不再按表头匹配
+		if colIndex, ok := fixedColumnIndex(tagOptions); ok {
+			name := headerName(excel)
+			if name == "" {
+				name = ColumnIndexToName(colIndex)
+			}
+			p.fixedColumns = append(p.fixedColumns, &fixedColumn{colIndex: colIndex, name: name, mappingField: m})
+			sort.SliceStable(p.fixedColumns, func(i, j int) bool { return p.fixedColumns[i].colIndex < p.fixedColumns[j].colIndex })
+			continue
+		}
+		p.fieldNames = append(p.fieldNames, fieldName)
+		p.fieldTitles[fieldName] = headerName(excel)
+		//name(手机号码|手机号|Phone) 中的每个名称都可匹配表头
+		for _, alias := range strings.Split(tagOptions[nameTag], "|") {
+			p.fieldMapping[NormalizeHeader(alias)] = m
+		}
+	}
+}
+
+type fixedColumn struct {
+	colIndex     int
+	name         string
+	mappingField map[string]string
+}
+
+// fixedColumnIndex 解析 col(C) 或 index(2)，index 与 ColumnIndexToName 一致从0开始
+func fixedColumnIndex(tagOptions map[string]string) (int, bool) {
+	if col, ok := tagOptions[colTag]; ok {
+		colNum, err := excelize.ColumnNameToNumber(strings.TrimSpace(col))
+		if err == nil {
+			return colNum - 1, true
+		}
+	}
+	if index, ok := tagOptions[indexTag]; ok {
+		colIndex, err := strconv.Atoi(strings.TrimSpace(index))
+		if err == nil && colIndex >= 0 {
+			return colIndex, true
+		}
+	}
+	return 0, false
+}
+
+// generateLeafMapping 多级表头名称按最后一级标题索引，有歧义的不收录
+func (p *parser) generateLeafMapping() {
+	p.leafMapping = make(map[string]map[string]string)
+	ambiguous := make(map[string]bool)
+	for name, m := range p.fieldMapping {
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			continue
+		}
+		leaf := name[i+1:]
+		if _, ok := p.leafMapping[leaf]; ok {
+			ambiguous[leaf] = true
+		}
+		p.leafMapping[leaf] = m
+	}
+	for leaf := range ambiguous {
+		delete(p.leafMapping, leaf)
+	}
+}
+
+// parseTagOptions 解析 excel 标签，形如 name(姓名);unique(true);required，括号内的分号不作为分隔符
+func parseTagOptions(excel string) map[string]string {
+	tagOptions := make(map[string]string)
+	var tokens []string
+	depth, start := 0, 0
+	for i := 0; i < len(excel); i++ {
+		switch excel[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ';':
+			if depth == 0 {
+				tokens = append(tokens, excel[start:i])
+				start = i + 1
+			}
+		}
+	}
+	tokens = append(tokens, excel[start:])
+
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		left := strings.Index(token, "(")
+		if left < 0 || !strings.HasSuffix(token, ")") {
+			tagOptions[token] = "true"
+			continue
+		}
+		tagOptions[strings.TrimSpace(token[:left])] = token[left+1 : len(token)-1]
 	}
+	return tagOptions
 }
 
 func stringMatchExport(str string, reg *regexp.Regexp) (res string, err error) {
@@ -86,153 +242,706 @@ func stringMatchExport(str string, reg *regexp.Regexp) (res string, err error) {
 	return reg.FindStringSubmatch(str)[1], nil
 }
 
-func (p *parser) ParseContent(file *os.File, mappingHeaderRow int, dataStartRow int) (*Result, error) {
-	if mappingHeaderRow-1 < 0 {
-		return nil, errors.New("no excel mapping header position is specified")
-	}
-	if mappingHeaderRow >= dataStartRow {
-		return nil, errors.New("mapping header row position cannot be greater than or equal to the beginning of the data row")
-	}
-	if err := p.readExcel(file); err != nil {
-		return nil, err
-	}
-	p.uniqueMap = make(map[int][]string)
-	p.sheetName = p.file.GetSheetName(0)
-	rows, err := p.file.GetRows(p.sheetName)
-	if err != nil {
+func (p *parser) ParseContent(reader io.Reader, opts *BindOptions) (*Result, error) {
+	if err := p.readExcel(reader); err != nil {
 		return nil, err
 	}
-	if len(rows) < dataStartRow {
-		return nil, errors.New("excel file valid data behavior is empty")
-	}
-	//excel数据行数限制
-	if len(rows)-(dataStartRow-1) > AllowMaxRow {
-		return nil, errors.New("data overrun")
+	//关闭时删除 excelize 为大文件生成的临时文件
+	defer func(file *excelize.File) {
+		_ = file.Close()
+	}(p.file)
+	return p.parseSheetContent(opts)
+}
+
+// ParseEach 基于行迭代器逐行解析，内存占用与文件大小无关，MaxRow 大于0时才限制数据行数
+func (p *parser) ParseEach(reader io.Reader, opts *BindOptions, fn func(body any, rowNum int, errList []*CellError) error) error {
+	if err := p.readExcel(reader); err != nil {
+		return err
 	}
+	defer func(file *excelize.File) {
+		_ = file.Close()
+	}(p.file)
+	return p.parseSheet(opts, fn)
+}
 
+func (p *parser) parseSheetContent(opts *BindOptions) (*Result, error) {
+	opts = opts.normalize()
+	if opts.MaxRow == 0 {
+		opts.MaxRow = AllowMaxRow
+	}
 	res := new(Result)
 	res.mappingResults = make([]any, 0)
-	if err := p.rows(rows, mappingHeaderRow, dataStartRow, res); err != nil {
+	err := p.parseSheet(opts, func(body any, rowNum int, errList []*CellError) error {
+		if len(errList) != 0 {
+			if res.cellErrors == nil {
+				res.cellErrors = map[int][]*CellError{rowNum: errList}
+			} else {
+				res.cellErrors[rowNum] = errList
+			}
+		}
+		if len(errList) == 0 {
+			res.validRows = append(res.validRows, &ImportRow[any]{RowNum: rowNum, Data: body})
+		}
+		if len(res.cellErrors) != 0 {
+			return nil
+		}
+		res.mappingResults = append(res.mappingResults, body)
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	res.headerRow, res.dataStartRow = p.headerRow, p.dataStartRow
+	res.missingColumns, res.unknownColumns, res.duplicateColumns = p.missingColumns, p.unknownColumns, p.duplicateColumns
 	return res, nil
 }
 
-func (p *parser) readExcel(file *os.File) (err error) {
-	var allowExtMap = map[string]bool{
-		".xlsx": true,
+func (p *parser) parseSheet(opts *BindOptions, fn func(body any, rowNum int, errList []*CellError) error) error {
+	opts = opts.normalize()
+	//按固定列绑定时可以没有表头
+	if opts.HeaderRow-1 < 0 && len(p.fixedColumns) == 0 {
+		return errors.New("no excel mapping header position is specified")
 	}
-	ext := path.Ext(file.Name())
-	//判断文件后缀
-	if _, ok := allowExtMap[ext]; !ok {
-		return fmt.Errorf("file request format error，support XLSX")
+	if opts.HeaderRow+opts.HeaderRows-1 >= opts.DataStartRow {
+		return errors.New("mapping header row position cannot be greater than or equal to the beginning of the data row")
 	}
-	p.file, err = excelize.OpenReader(file)
-	return err
+	p.uniqueIndex = make(map[string]map[string]int)
+	p.headerMapping = nil
+	p.mergeCells, p.mergeLoaded = nil, false
+	p.messages = opts.messageCatalog()
+	p.location = opts.TimeZone
+	if props, err := p.file.GetWorkbookProps(); err == nil && props.Date1904 != nil {
+		p.date1904 = *props.Date1904
+	}
+	if err := p.selectSheet(opts); err != nil {
+		return err
+	}
+	if err := p.rows(opts, fn); err != nil {
+		return fmt.Errorf("sheet[%s] %w", p.sheetName, err)
+	}
+	return nil
 }
 
-func (p *parser) rows(rows [][]string, mappingHeaderRow, dataStartRow int, res *Result) error {
-	for rowIndex := dataStartRow - 1; rowIndex < len(rows); rowIndex++ {
-		res.rowIndex = rowIndex
-		errList := make([]string, 0)
-		newBodyVal := reflect.New(p.val.Type().Elem())
-		newBodyVal.Elem().Set(p.val.Elem())
-		for colIndex, col := range rows[rowIndex] {
-			if colIndex >= len(rows[mappingHeaderRow-1]) {
-				continue
+func (p *parser) selectSheet(opts *BindOptions) error {
+	sheetList := p.file.GetSheetList()
+	if opts.SheetName != "" {
+		for _, sheetName := range sheetList {
+			if sheetName == opts.SheetName {
+				p.sheetName = sheetName
+				return nil
 			}
-			mappingHeader := rows[mappingHeaderRow-1][colIndex]
-			//去除列的前后空格
-			colVal := strings.TrimSpace(col)
-			mappingField, ok := p.fieldMapping[strings.TrimSpace(mappingHeader)]
-			if !ok {
-				continue
+		}
+		return fmt.Errorf("sheet[%s] not found", opts.SheetName)
+	}
+	if opts.FirstNonEmptySheet {
+		for _, sheetName := range sheetList {
+			empty, err := p.isEmptySheet(sheetName)
+			if err != nil {
+				return fmt.Errorf("sheet[%s] %w", sheetName, err)
 			}
-			// 列唯一性校验
-			errList = append(errList, p.uniqueFormat(rows, mappingHeader, &colVal, rowIndex, colIndex, mappingField)...)
-			//格式化时间
-			errList = append(errList, p.dateFormat(mappingHeader, &colVal, mappingField)...)
-			//值映射转换
-			mappingErrList := p.mappingFormat(mappingHeader, &colVal, mappingField)
-			errList = append(errList, mappingErrList...)
-			if len(mappingErrList) != 0 {
-				continue
+			if !empty {
+				p.sheetName = sheetName
+				return nil
 			}
-			//参数赋值
-			errs, err := p.parseValue(newBodyVal, mappingField[nameTag], mappingHeader, colVal)
+		}
+		return errors.New("no non-empty sheet found")
+	}
+	if opts.SheetIndex < 0 || opts.SheetIndex >= len(sheetList) {
+		return fmt.Errorf("sheet index[%d] out of range", opts.SheetIndex)
+	}
+	p.sheetName = sheetList[opts.SheetIndex]
+	return nil
+}
+
+func (p *parser) isEmptySheet(sheetName string) (bool, error) {
+	iter, err := p.file.Rows(sheetName)
+	if err != nil {
+		return false, err
+	}
+	defer func(iter *excelize.Rows) {
+		_ = iter.Close()
+	}(iter)
+
+	for iter.Next() {
+		row, err := iter.Columns()
+		if err != nil {
+			return false, err
+		}
+		if !isBlankRow(row) {
+			return false, nil
+		}
+	}
+	return true, iter.Error()
+}
+
+func (p *parser) readExcel(reader io.Reader) (err error) {
+	p.file, err = openExcelReader(reader)
+	return err
+}
+
+// openExcelReader 根据文件内容（zip文件头）而非后缀判断格式
+func openExcelReader(reader io.Reader) (*excelize.File, error) {
+	br := bufio.NewReader(reader)
+	head, err := br.Peek(len(zipSignature))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if !bytes.Equal(head, zipSignature) {
+		return nil, fmt.Errorf("file request format error，support XLSX")
+	}
+	return excelize.OpenReader(br)
+}
+
+func (p *parser) rows(opts *BindOptions, fn func(body any, rowNum int, errList []*CellError) error) error {
+	iter, err := p.file.Rows(p.sheetName)
+	if err != nil {
+		return err
+	}
+	defer func(iter *excelize.Rows) {
+		_ = iter.Close()
+	}(iter)
+	//日期单元格的显示文本无法解析时需要原始序列号，另开一个迭代器与 iter 同步读取
+	var rawIter *excelize.Rows
+	if p.rawValues {
+		if rawIter, err = p.file.Rows(p.sheetName); err != nil {
+			return err
+		}
+		defer func(rawIter *excelize.Rows) {
+			_ = rawIter.Close()
+		}(rawIter)
+	}
+
+	p.headerRow, p.dataStartRow = opts.HeaderRow, opts.DataStartRow
+	//自动识别表头时先缓存前若干行
+	var buffered, rawBuffered [][]string
+	if opts.AutoDetectHeader && !opts.NoHeader {
+		for len(buffered) < opts.HeaderScanRows {
+			row, raw, ok, err := nextRow(iter, rawIter)
 			if err != nil {
 				return err
 			}
-			errList = append(errList, errs...)
+			if !ok {
+				break
+			}
+			buffered = append(buffered, row)
+			rawBuffered = append(rawBuffered, raw)
 		}
-		if len(errList) != 0 {
-			if res.errors == nil {
-				res.errors = map[int][]string{rowIndex + 1: errList}
-			} else {
-				res.errors[rowIndex+1] = errList
+		detectedRow := p.detectHeaderRow(buffered)
+		if detectedRow == 0 {
+			return errors.New("no header row matched in the first rows")
+		}
+		//多行表头时识别到的为最后一行表头
+		p.headerRow = max(detectedRow-opts.HeaderRows+1, 1)
+		p.dataStartRow = detectedRow + 1
+	}
+
+	var mergedValues map[int]map[int]string
+	if opts.FillMergedCells {
+		if mergedValues, err = p.mergedDataValues(); err != nil {
+			return err
+		}
+	}
+
+	var header []string
+	var headerRows [][]string
+	lastHeaderRow := p.headerRow + opts.HeaderRows - 1
+	rowNum, dataRows := 0, 0
+	handle := func(row, raw []string) error {
+		rowNum++
+		if values, ok := mergedValues[rowNum]; ok {
+			row = fillMergedValues(row, values)
+			delete(mergedValues, rowNum)
+		}
+		if rowNum >= p.headerRow && rowNum <= lastHeaderRow {
+			headerRows = append(headerRows, row)
+			if rowNum == lastHeaderRow {
+				if header, err = p.buildHeader(headerRows); err != nil {
+					return err
+				}
+				return p.validateHeader(header, opts)
 			}
+			return nil
+		}
+		if rowNum < p.dataStartRow || isBlankRow(row) {
+			return nil
+		}
+		dataRows++
+		//excel数据行数限制
+		if opts.MaxRow > 0 && dataRows > opts.MaxRow {
+			return errors.New("data overrun")
+		}
+		p.rawRow = raw
+		body, errList, err := p.row(header, row, rowNum)
+		if err != nil {
+			return err
 		}
-		p.body = newBodyVal.Interface()
-		if _, ok := res.HasError(); ok {
+		return fn(body, rowNum, errList)
+	}
+
+	for i, row := range buffered {
+		if err = handle(row, rawBuffered[i]); err != nil {
+			return err
+		}
+	}
+	for {
+		row, raw, ok, err := nextRow(iter, rawIter)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		if err = handle(row, raw); err != nil {
+			return err
+		}
+	}
+	if err = iter.Error(); err != nil {
+		return err
+	}
+	if dataRows == 0 {
+		return errors.New("excel file valid data behavior is empty")
+	}
+	return nil
+}
+
+// nextRow 读取下一行的显示值，rawIter 不为空时同步读取同一行的原始值
+func nextRow(iter, rawIter *excelize.Rows) (row, raw []string, ok bool, err error) {
+	if !iter.Next() {
+		return nil, nil, false, nil
+	}
+	if row, err = iter.Columns(); err != nil {
+		return nil, nil, false, err
+	}
+	if rawIter != nil && rawIter.Next() {
+		if raw, err = rawIter.Columns(excelize.Options{RawCellValue: true}); err != nil {
+			return nil, nil, false, err
+		}
+	}
+	return row, raw, true, nil
+}
+
+// validateHeader 比对表头与字段映射，记录缺失、无法识别和重复的列，按配置报错
+func (p *parser) validateHeader(header []string, opts *BindOptions) error {
+	p.missingColumns, p.unknownColumns, p.duplicateColumns, p.defaultFields = nil, nil, nil, nil
+	//每列只匹配一次，数据行直接复用
+	p.headerMapping = make([]map[string]string, len(header))
+	var missing, unknown, duplicates []string
+	matched := make(map[string]bool)
+	for colIndex, mappingHeader := range header {
+		if strings.TrimSpace(mappingHeader) == "" {
 			continue
 		}
-		res.mappingResults = append(res.mappingResults, p.body)
+		mappingField, ok := p.lookupMapping(mappingHeader)
+		if !ok {
+			//存在 extra 字段时未映射的列会被收集，不视为无法识别
+			if p.extraField == "" && !p.isFixedColumn(colIndex) {
+				p.unknownColumns = append(p.unknownColumns, mappingHeader)
+				unknown = append(unknown, mappingHeader)
+			}
+			continue
+		}
+		//重复的列只绑定第一列，其余列不绑定字段
+		if matched[mappingField[nameTag]] {
+			p.duplicateColumns = append(p.duplicateColumns, mappingHeader)
+			duplicates = append(duplicates, mappingHeader)
+			continue
+		}
+		matched[mappingField[nameTag]] = true
+		p.headerMapping[colIndex] = mappingField
+	}
+
+	for _, fieldName := range p.fieldNames {
+		if matched[fieldName] {
+			continue
+		}
+		title, mappingField := p.fieldTitles[fieldName], p.fieldMappingByName(fieldName)
+		p.missingColumns = append(p.missingColumns, title)
+		if _, ok := mappingField[defaultTag]; ok {
+			p.defaultFields = append(p.defaultFields, mappingField)
+		}
+		if opts.RequireAllColumns || mappingField[requiredTag] == "true" {
+			missing = append(missing, title)
+		}
+	}
+
+	if !opts.StrictHeader {
+		unknown, duplicates = nil, nil
+	}
+	if len(missing) == 0 && len(unknown) == 0 && len(duplicates) == 0 {
+		return nil
+	}
+	return &HeaderError{Sheet: p.sheetName, Missing: missing, Unknown: unknown, Duplicates: duplicates, messages: p.messages}
+}
+
+func (p *parser) isFixedColumn(colIndex int) bool {
+	for _, fc := range p.fixedColumns {
+		if fc.colIndex == colIndex {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) fieldMappingByName(fieldName string) map[string]string {
+	for _, m := range p.fieldMapping {
+		if m[nameTag] == fieldName {
+			return m
+		}
 	}
 	return nil
 }
 
-func (p *parser) uniqueFormat(rows [][]string, mappingHeader string, col *string, rowIndex, colIndex int, mappingField map[string]string) []string {
-	errList := make([]string, 0)
-	format, ok := mappingField[uniqueTag]
-	if !ok || format != "true" {
-		return errList
+// detectHeaderRow 返回匹配表头名称最多的行号，均不匹配时返回0
+func (p *parser) detectHeaderRow(rows [][]string) int {
+	headerRow, maxMatched := 0, 0
+	for i, row := range rows {
+		matched := 0
+		for _, col := range row {
+			if _, ok := p.lookupMapping(col); ok && strings.TrimSpace(col) != "" {
+				matched++
+			}
+		}
+		if matched > maxMatched {
+			headerRow, maxMatched = i+1, matched
+		}
+	}
+	return headerRow
+}
+
+// buildHeader 合并多行表头，合并单元格的值会填充到其覆盖的每一列，各级标题以/连接
+func (p *parser) buildHeader(headerRows [][]string) ([]string, error) {
+	if len(headerRows) == 1 {
+		return headerRows[0], nil
+	}
+
+	width := 0
+	for _, row := range headerRows {
+		width = max(width, len(row))
+	}
+	mergeCells, err := p.loadMergeCells()
+	if err != nil {
+		return nil, err
+	}
+	type mergeRange struct {
+		startCol, startRow, endCol, endRow int
+		value                              string
+	}
+	var ranges []mergeRange
+	for _, mc := range mergeCells {
+		startCol, startRow, err := excelize.CellNameToCoordinates(mc.GetStartAxis())
+		if err != nil {
+			return nil, err
+		}
+		endCol, endRow, err := excelize.CellNameToCoordinates(mc.GetEndAxis())
+		if err != nil {
+			return nil, err
+		}
+		if endRow < p.headerRow || startRow > p.headerRow+len(headerRows)-1 {
+			continue
+		}
+		width = max(width, endCol)
+		ranges = append(ranges, mergeRange{startCol, startRow, endCol, endRow, mc.GetCellValue()})
+	}
+
+	grid := make([][]string, len(headerRows))
+	for r, row := range headerRows {
+		grid[r] = make([]string, width)
+		copy(grid[r], row)
+	}
+	for _, mr := range ranges {
+		for r := max(mr.startRow, p.headerRow); r <= min(mr.endRow, p.headerRow+len(headerRows)-1); r++ {
+			for c := mr.startCol; c <= mr.endCol; c++ {
+				grid[r-p.headerRow][c-1] = mr.value
+			}
+		}
 	}
-	_, ok = p.uniqueMap[colIndex]
-	if !ok {
-		p.uniqueMap[colIndex] = make([]string, 0)
-		for index := 0; index < len(rows); index++ {
-			if len(rows[index]) <= colIndex {
-				p.uniqueMap[colIndex] = append(p.uniqueMap[colIndex], rows[index][0])
+
+	header := make([]string, width)
+	for c := 0; c < width; c++ {
+		var titles []string
+		for r := range grid {
+			title := strings.TrimSpace(grid[r][c])
+			//纵向合并的标题只保留一次
+			if title == "" || (len(titles) > 0 && titles[len(titles)-1] == title) {
 				continue
 			}
-			p.uniqueMap[colIndex] = append(p.uniqueMap[colIndex], rows[index][colIndex])
+			titles = append(titles, title)
 		}
+		header[c] = strings.Join(titles, "/")
 	}
-	cols := p.uniqueMap[colIndex]
-	for i, val := range cols {
-		if i != rowIndex && val != "" && val == *col {
-			errList = append(errList, fmt.Sprintf("%s[%s]不可重复", mappingHeader, *col))
-			break
+	return header, nil
+}
+
+func (p *parser) loadMergeCells() ([]excelize.MergeCell, error) {
+	if p.mergeLoaded {
+		return p.mergeCells, nil
+	}
+	mergeCells, err := p.file.GetMergeCells(p.sheetName)
+	if err != nil {
+		return nil, err
+	}
+	p.mergeCells, p.mergeLoaded = mergeCells, true
+	return p.mergeCells, nil
+}
+
+// mergedDataValues 返回数据区域中合并单元格覆盖的每个单元格的值，按行号和列索引组织
+func (p *parser) mergedDataValues() (map[int]map[int]string, error) {
+	mergeCells, err := p.loadMergeCells()
+	if err != nil {
+		return nil, err
+	}
+	mergedValues := make(map[int]map[int]string)
+	for _, mc := range mergeCells {
+		startCol, startRow, err := excelize.CellNameToCoordinates(mc.GetStartAxis())
+		if err != nil {
+			return nil, err
+		}
+		endCol, endRow, err := excelize.CellNameToCoordinates(mc.GetEndAxis())
+		if err != nil {
+			return nil, err
+		}
+		for r := max(startRow, p.dataStartRow); r <= endRow; r++ {
+			if mergedValues[r] == nil {
+				mergedValues[r] = make(map[int]string)
+			}
+			for c := startCol; c <= endCol; c++ {
+				mergedValues[r][c-1] = mc.GetCellValue()
+			}
 		}
 	}
-	return errList
+	return mergedValues, nil
+}
+
+func fillMergedValues(row []string, values map[int]string) []string {
+	for colIndex, value := range values {
+		for len(row) <= colIndex {
+			row = append(row, "")
+		}
+		row[colIndex] = value
+	}
+	return row
 }
 
-func (p *parser) dateFormat(mappingHeader string, col *string, mappingField map[string]string) []string {
-	errList := make([]string, 0)
+// lookupMapping 先按完整的多级表头匹配，再按最后一级标题匹配
+func (p *parser) lookupMapping(header string) (map[string]string, bool) {
+	normalized := NormalizeHeader(header)
+	if mappingField, ok := p.fieldMapping[normalized]; ok {
+		return mappingField, true
+	}
+	if i := strings.LastIndex(normalized, "/"); i >= 0 {
+		mappingField, ok := p.fieldMapping[normalized[i+1:]]
+		return mappingField, ok
+	}
+	if mappingField, ok := p.leafMapping[normalized]; ok {
+		return mappingField, true
+	}
+	return nil, false
+}
+
+func (p *parser) row(header, row []string, rowNum int) (any, []*CellError, error) {
+	errList := make([]*CellError, 0)
+	p.rowUnique = make(map[string]*uniquePart)
+	newBodyVal := reflect.New(p.val.Type().Elem())
+	newBodyVal.Elem().Set(p.val.Elem())
+	//浅拷贝会使各行共用原型中的 extra map，每行重新创建
+	if p.extraField != "" {
+		extra := p.fieldByPath(newBodyVal, p.extraField)
+		rowExtra := reflect.MakeMap(extraType)
+		for _, key := range extra.MapKeys() {
+			rowExtra.SetMapIndex(key, extra.MapIndex(key))
+		}
+		extra.Set(rowExtra)
+	}
+	for colIndex, mappingHeader := range header {
+		var mappingField map[string]string
+		if colIndex < len(p.headerMapping) {
+			mappingField = p.headerMapping[colIndex]
+		}
+		if mappingField == nil {
+			p.bindExtra(newBodyVal, mappingHeader, row, colIndex)
+			continue
+		}
+		cellErrList, err := p.bindCell(newBodyVal, mappingHeader, row, colIndex, rowNum, mappingField)
+		if err != nil {
+			return nil, nil, err
+		}
+		errList = append(errList, cellErrList...)
+	}
+	//表头中缺失但设置了默认值的字段
+	for _, mappingField := range p.defaultFields {
+		cellErrList, err := p.bindCell(newBodyVal, p.fieldTitles[mappingField[nameTag]], row, -1, rowNum, mappingField)
+		if err != nil {
+			return nil, nil, err
+		}
+		errList = append(errList, cellErrList...)
+	}
+	//按固定列绑定的字段
+	for _, fc := range p.fixedColumns {
+		mappingHeader := fc.name
+		if fc.colIndex < len(header) && strings.TrimSpace(header[fc.colIndex]) != "" {
+			mappingHeader = header[fc.colIndex]
+		}
+		cellErrList, err := p.bindCell(newBodyVal, mappingHeader, row, fc.colIndex, rowNum, fc.mappingField)
+		if err != nil {
+			return nil, nil, err
+		}
+		errList = append(errList, cellErrList...)
+	}
+	errList = append(errList, p.uniqueGroupFormat(rowNum, errList)...)
+	p.body = newBodyVal.Interface()
+	return p.body, p.rowHooks(p.body, rowNum, errList), nil
+}
+
+// bindExtra 将未映射的列写入 excel:"extra" 字段
+func (p *parser) bindExtra(bodyVal reflect.Value, mappingHeader string, row []string, colIndex int) {
+	mappingHeader = strings.TrimSpace(mappingHeader)
+	if p.extraField == "" || mappingHeader == "" || p.isFixedColumn(colIndex) {
+		return
+	}
+	var colVal string
+	if colIndex < len(row) {
+		colVal = strings.TrimSpace(row[colIndex])
+	}
+	val := p.fieldByPath(bodyVal, p.extraField)
+	val.SetMapIndex(reflect.ValueOf(mappingHeader), reflect.ValueOf(colVal))
+}
+
+func (p *parser) bindCell(bodyVal reflect.Value, mappingHeader string, row []string, colIndex, rowNum int, mappingField map[string]string) ([]*CellError, error) {
+	//去除列的前后空格，colIndex 小于0表示表头中缺失的列；行尾的空单元格不会被读取，表头中存在即视为有值
+	var colVal, column string
+	present := colIndex >= 0 && (colIndex < len(row) || colIndex < len(p.headerMapping))
+	if colIndex >= 0 && colIndex < len(row) {
+		colVal = strings.TrimSpace(row[colIndex])
+	}
+	if colIndex >= 0 {
+		column = ColumnIndexToName(colIndex)
+	}
+	meta := CellMeta{
+		Sheet:  p.sheetName,
+		Row:    rowNum,
+		Column: column,
+		Header: mappingHeader,
+		Field:  p.structFields[mappingField[nameTag]],
+	}
+	meta.DateLayouts, _ = splitDateTag(mappingField[dateTag])
+	meta.Location = p.location
+	meta.Date1904 = p.date1904
+	cellErrList, err := p.cell(bodyVal, meta, colVal, colIndex, present, mappingField)
+	if err != nil {
+		return nil, err
+	}
+	for _, ce := range cellErrList {
+		ce.Sheet = meta.Sheet
+		ce.Row = meta.Row
+		ce.Column = meta.Column
+		ce.Header = meta.Header
+		ce.Value = colVal
+		ce.Field = mappingField[nameTag]
+	}
+	return cellErrList, nil
+}
+
+func (p *parser) cell(bodyVal reflect.Value, meta CellMeta, colVal string, colIndex int, present bool, mappingField map[string]string) ([]*CellError, error) {
+	mappingHeader := meta.Header
+	//空单元格或缺失的列使用 default(...) 的值
+	if defaultVal, ok := mappingField[defaultTag]; ok && colVal == "" {
+		colVal, present = defaultVal, true
+	}
+	//标签校验规则
+	errList, err := p.validate(mappingHeader, colVal, mappingField)
+	if err != nil || len(errList) != 0 || !present {
+		return errList, err
+	}
+	// 列唯一性校验
+	errList = append(errList, p.uniqueFormat(meta, colVal, mappingField)...)
+	//格式化时间
+	errList = append(errList, p.dateFormat(mappingHeader, &colVal, meta, mappingField)...)
+	//值映射转换
+	mappingErrList := p.mappingFormat(mappingHeader, &colVal, meta, mappingField)
+	errList = append(errList, mappingErrList...)
+	if len(mappingErrList) != 0 {
+		return errList, nil
+	}
+	//参数赋值
+	errs, err := p.parseValue(bodyVal, mappingField[nameTag], colVal, meta)
+	if err != nil {
+		return nil, err
+	}
+	return append(errList, errs...), nil
+}
+
+func (p *parser) newCellError(code CellErrorCode, key string, mappingHeader, col string) *CellError {
+	return p.newCellErrorWithParams(code, key, mappingHeader, col, nil)
+}
+
+func (p *parser) newCellErrorWithParams(code CellErrorCode, key string, mappingHeader, col string, params map[string]string) *CellError {
+	if params == nil {
+		params = make(map[string]string)
+	}
+	params["header"] = mappingHeader
+	params["value"] = col
+	return newCellError(code, p.messages.render(key, params))
+}
+
+func isBlankRow(row []string) bool {
+	for _, col := range row {
+		if strings.TrimSpace(col) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *parser) dateFormat(mappingHeader string, col *string, meta CellMeta, mappingField map[string]string) []*CellError {
+	errList := make([]*CellError, 0)
 	format, ok := mappingField[dateTag]
 	if !ok || format == "" {
 		return errList
 	}
-	formats := strings.SplitN(format, ",", 2)
-	if *col == "" || len(formats) != 2 {
+	//时间类型字段由转换器直接解析
+	if isTimeField(meta.Field) {
+		return errList
+	}
+	layouts, outLayout := splitDateTag(format)
+	if *col == "" || len(layouts) == 0 || outLayout == "" {
 		return errList
 	}
-	location, err := time.ParseInLocation(formats[0], *col, time.Local)
+	t, err := ParseExcelTime(*col, layouts, meta.Location, meta.Date1904)
 	if err != nil {
-		errList = append(errList, fmt.Sprintf("%s单元格格式错误", mappingHeader))
+		if raw := p.rawCellValue(meta); raw != "" && raw != *col {
+			t, err = ParseExcelTime(raw, layouts, meta.Location, meta.Date1904)
+		}
+	}
+	if err != nil {
+		errList = append(errList, p.newCellError(CellErrorDate, MsgDate, mappingHeader, *col))
 		return errList
 	}
-	*col = location.Format(formats[1])
+	*col = t.Format(outLayout)
 	return errList
 }
 
-func (p *parser) mappingFormat(mappingHeader string, col *string, mappingField map[string]string) []string {
-	errList := make([]string, 0)
+// rawCellValue 当前行单元格未经数字格式化的原始值，用于日期单元格的序列号
+func (p *parser) rawCellValue(meta CellMeta) string {
+	if meta.Column == "" {
+		return ""
+	}
+	colNum, err := excelize.ColumnNameToNumber(meta.Column)
+	if err != nil || colNum > len(p.rawRow) {
+		return ""
+	}
+	return strings.TrimSpace(p.rawRow[colNum-1])
+}
+
+func (p *parser) mappingFormat(mappingHeader string, col *string, meta CellMeta, mappingField map[string]string) []*CellError {
+	errList := make([]*CellError, 0)
 	format, ok := mappingField[mappingTag]
 	if !ok || format == "" {
 		return errList
 	}
+	//指针字段的空值不做映射，保持 nil；设置了 default(...) 时空值已被替换
+	if *col == "" && meta.Field.Type != nil && meta.Field.Type.Kind() == reflect.Ptr {
+		return errList
+	}
 	mappingValues := make(map[string]string)
 	formatStr := strings.Split(format, ",")
 	for _, format := range formatStr {
@@ -247,40 +956,64 @@ func (p *parser) mappingFormat(mappingHeader string, col *string, mappingField m
 		*col = val
 		return errList
 	}
-	errList = append(errList, fmt.Sprintf("%s单元格存在非法输入", mappingHeader))
+	errList = append(errList, p.newCellError(CellErrorMapping, MsgMapping, mappingHeader, *col))
 	return errList
 }
 
-func (p *parser) parseValue(val reflect.Value, fieldAddr, mappingHeader, col string) ([]string, error) {
-	errList := make([]string, 0)
+func (p *parser) parseValue(val reflect.Value, fieldAddr, col string, meta CellMeta) ([]*CellError, error) {
+	return p.parse(p.fieldByPath(val, fieldAddr), col, meta)
+}
+
+// fieldByPath 按 A.B 路径取字段，途经的空结构体指针会被初始化
+func (p *parser) fieldByPath(val reflect.Value, fieldAddr string) reflect.Value {
 	fields := strings.Split(fieldAddr, ".")
-	if len(fields) == 0 {
-		return errList, nil
-	}
-	for _, field := range fields {
+	for i, field := range fields {
 		if val.Kind() == reflect.Ptr {
 			val = val.Elem()
 		}
 		val = val.FieldByName(field)
-		errs, err := p.parse(val, col, mappingHeader)
-		if err != nil {
-			return errList, err
+		if i == len(fields)-1 {
+			break
+		}
+		//初始化嵌套结构体指针
+		if val.Kind() == reflect.Ptr && val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
 		}
-		errList = append(errList, errs...)
 	}
-	return errList, nil
+	return val
 }
 
-func (p *parser) parse(val reflect.Value, col, mappingHeader string) ([]string, error) {
-	errList := make([]string, 0)
-	var err error
+func (p *parser) parse(val reflect.Value, col string, meta CellMeta) ([]*CellError, error) {
+	errList := make([]*CellError, 0)
+	mappingHeader := meta.Header
+	//空值统一处理：指针字段为 nil，其他字段为零值，ExcelCellUnmarshaler 自行处理空值
+	if col == "" && !isCellUnmarshaler(val) {
+		val.Set(reflect.Zero(val.Type()))
+		return errList, nil
+	}
+	//自定义类型转换
+	handled, err := convertCell(val, col, meta)
+	//日期单元格读取到的是显示文本，格式不匹配时按原始序列号重试
+	if handled && err != nil && val.Type() == timeType {
+		if raw := p.rawCellValue(meta); raw != "" && raw != col {
+			handled, err = convertCell(val, raw, meta)
+		}
+	}
+	if handled {
+		if err != nil && val.Type() == timeType {
+			errList = append(errList, p.newCellError(CellErrorDate, MsgDate, mappingHeader, col))
+		} else if err != nil {
+			errList = append(errList, p.newCellErrorWithParams(CellErrorType, MsgTypeConvert, mappingHeader, col, map[string]string{"type": val.Type().String()}))
+		}
+		return errList, nil
+	}
 	switch val.Kind() {
 	case reflect.String:
 		val.SetString(col)
 	case reflect.Bool:
 		parseBool, err := strconv.ParseBool(col)
 		if err != nil {
-			errList = append(errList, fmt.Sprintf("%s单元格非法输入,参数非bool类型值", mappingHeader))
+			errList = append(errList, p.newCellError(CellErrorType, MsgTypeBool, mappingHeader, col))
 		}
 		val.SetBool(parseBool)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -288,7 +1021,7 @@ func (p *parser) parse(val reflect.Value, col, mappingHeader string) ([]string,
 		if col != "" {
 			value, err = strconv.ParseInt(col, 10, 64)
 			if err != nil {
-				errList = append(errList, fmt.Sprintf("%s单元格非法输入,参数非整形数值", mappingHeader))
+				errList = append(errList, p.newCellError(CellErrorType, MsgTypeInt, mappingHeader, col))
 			}
 		}
 		val.SetInt(value)
@@ -297,7 +1030,7 @@ func (p *parser) parse(val reflect.Value, col, mappingHeader string) ([]string,
 		if col != "" {
 			value, err = strconv.ParseUint(col, 10, 64)
 			if err != nil {
-				errList = append(errList, fmt.Sprintf("%s单元格非法输入,参数非整形数值", mappingHeader))
+				errList = append(errList, p.newCellError(CellErrorType, MsgTypeInt, mappingHeader, col))
 			}
 		}
 		val.SetUint(value)
@@ -306,7 +1039,7 @@ func (p *parser) parse(val reflect.Value, col, mappingHeader string) ([]string,
 		if col != "" {
 			value, err = strconv.ParseFloat(col, 64)
 			if err != nil {
-				errList = append(errList, fmt.Sprintf("%s单元格非法输入,参数非浮点型数值", mappingHeader))
+				errList = append(errList, p.newCellError(CellErrorType, MsgTypeFloat, mappingHeader, col))
 			}
 		}
 		val.SetFloat(value)
@@ -316,8 +1049,8 @@ func (p *parser) parse(val reflect.Value, col, mappingHeader string) ([]string,
 		//初始化指针
 		value := reflect.New(val.Type().Elem())
 		val.Set(value)
-		var errs []string
-		errs, err = p.parse(val.Elem(), col, mappingHeader)
+		var errs []*CellError
+		errs, err = p.parse(val.Elem(), col, meta)
 		if err != nil {
 			break
 		}
@@ -329,13 +1062,71 @@ func (p *parser) parse(val reflect.Value, col, mappingHeader string) ([]string,
 }
 
 type Result struct {
-	errors         map[int][]string
-	mappingResults []any
-	rowIndex       int
+	cellErrors       map[int][]*CellError
+	mappingResults   []any
+	headerRow        int
+	dataStartRow     int
+	missingColumns   []string
+	unknownColumns   []string
+	duplicateColumns []string
+	validRows        []*ImportRow[any]
+}
+
+// ImportRow 绑定成功的行及其在 excel 中的行号
+type ImportRow[T any] struct {
+	RowNum int
+	Data   T
 }
 
 func (r *Result) HasError() (map[int][]string, bool) {
-	return r.errors, len(r.errors) != 0
+	if len(r.cellErrors) == 0 {
+		return nil, false
+	}
+	errs := make(map[int][]string, len(r.cellErrors))
+	for rowNum, cellErrors := range r.cellErrors {
+		for _, ce := range cellErrors {
+			errs[rowNum] = append(errs[rowNum], ce.Message)
+		}
+	}
+	return errs, true
+}
+
+func (r *Result) CellErrors() []*CellError {
+	var cellErrors []*CellError
+	for _, ces := range r.cellErrors {
+		cellErrors = append(cellErrors, ces...)
+	}
+	sortCellErrors(cellErrors)
+	return cellErrors
+}
+
+// HeaderRow 实际使用的表头行，自动识别表头时为识别结果
+func (r *Result) HeaderRow() int {
+	return r.headerRow
+}
+
+func (r *Result) DataStartRow() int {
+	return r.dataStartRow
+}
+
+// MissingColumns 表头中缺失的字段列
+func (r *Result) MissingColumns() []string {
+	return r.missingColumns
+}
+
+// UnknownColumns 表头中无法识别的列
+func (r *Result) UnknownColumns() []string {
+	return r.unknownColumns
+}
+
+// ValidRows 所有校验通过的行，存在错误行时也会返回
+func (r *Result) ValidRows() []*ImportRow[any] {
+	return r.validRows
+}
+
+// DuplicateColumns 表头中重复的列，同一字段按最后一列绑定
+func (r *Result) DuplicateColumns() []string {
+	return r.duplicateColumns
 }
 
 func (r *Result) List() []any {
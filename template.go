@@ -0,0 +1,229 @@
+package dgexcel
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type TemplateOptions struct {
+	SheetName string
+	// 示例数据，类型为 T 或 *T，写在表头下一行
+	Example any
+	// 填写说明，每个元素占一行，写入单独的工作表
+	Instructions []string
+	// 下拉选项和日期格式覆盖的数据行数，默认与 AllowMaxRow 一致
+	Rows   int
+	Locale string
+}
+
+// GenerateImportTemplate 按结构体的 excel 标签生成导入模板
+func GenerateImportTemplate[T any](opts ...*TemplateOptions) (*excelize.File, error) {
+	opt := &TemplateOptions{}
+	if len(opts) > 0 && opts[0] != nil {
+		opt = opts[0]
+	}
+	sheetName := opt.SheetName
+	if sheetName == "" {
+		sheetName = DefaultSheetName
+	}
+	rows := opt.Rows
+	if rows <= 0 {
+		rows = AllowMaxRow
+	}
+	if rows <= 0 || rows >= excelize.TotalRows {
+		rows = excelize.TotalRows - 1
+	}
+	catalog := newMessageCatalog(opt.Locale, nil)
+
+	xlsx := excelize.NewFile()
+	if sheetName != DefaultSheetName {
+		if err := xlsx.SetSheetName(DefaultSheetName, sheetName); err != nil {
+			return nil, err
+		}
+	}
+	centerStyleId := BuildCenterStyleId(xlsx)
+
+	typeOf := reflect.TypeOf(new(T)).Elem()
+	if typeOf.Kind() != reflect.Struct {
+		return nil, errors.New("template type must be struct")
+	}
+	//列与导入时的字段映射一致，包含嵌套结构体和固定列
+	p, err := newParser(new(T))
+	if err != nil {
+		return nil, err
+	}
+	var example reflect.Value
+	if opt.Example != nil {
+		example = reflect.ValueOf(opt.Example)
+	}
+
+	for _, column := range templateColumns(p) {
+		field := p.structFields[column.fieldName]
+		tagKey := field.Tag.Get(excelTag)
+		tagOptions := column.mappingField
+		colName := ColumnIndexToName(column.colIndex)
+		dataRange := colName + "2:" + colName + strconv.Itoa(rows+1)
+
+		widthVal, _ := stringMatchExport(tagKey, widthRegex)
+		width, _ := strconv.Atoi(widthVal)
+		if width == 0 {
+			width = 20
+		}
+		_ = xlsx.SetColWidth(sheetName, colName, colName, float64(width))
+
+		var comments []string
+		if tagOptions[requiredTag] == "true" {
+			comments = append(comments, catalog.render(MsgTplRequired, nil))
+		}
+		if _, enabled := uniqueGroup(tagOptions[uniqueTag]); enabled {
+			comments = append(comments, catalog.render(MsgTplUnique, nil))
+		}
+		if defaultVal, ok := tagOptions[defaultTag]; ok {
+			comments = append(comments, catalog.render(MsgTplDefault, map[string]string{"value": defaultVal}))
+		}
+
+		//日期列按第一个输入格式设置单元格格式
+		if layout := templateDateLayout(field, tagOptions[dateTag]); layout != "" {
+			numFmt := excelDateFormat(layout)
+			styleId, err := xlsx.NewStyle(&excelize.Style{CustomNumFmt: &numFmt})
+			if err == nil {
+				_ = xlsx.SetColStyle(sheetName, colName, styleId)
+			}
+			comments = append(comments, catalog.render(MsgTplDate, map[string]string{"layout": layout}))
+		}
+
+		//mapping 的显示值或 oneof 的可选值作为下拉选项
+		if options := templateDropList(tagOptions); len(options) > 0 {
+			dv := excelize.NewDataValidation(true)
+			dv.Sqref = dataRange
+			if err := dv.SetDropList(options); err == nil {
+				_ = xlsx.AddDataValidation(sheetName, dv)
+			}
+		}
+
+		cellIndex := colName + "1"
+		_ = xlsx.SetCellValue(sheetName, cellIndex, column.name)
+		_ = xlsx.SetCellStyle(sheetName, cellIndex, cellIndex, centerStyleId)
+		if len(comments) > 0 {
+			_ = xlsx.AddComment(sheetName, excelize.Comment{
+				Author:    commentAuthor,
+				Cell:      cellIndex,
+				Paragraph: []excelize.RichTextRun{{Text: strings.Join(comments, "\n")}},
+			})
+		}
+
+		if val, ok := templateExampleValue(example, column.fieldName); ok {
+			cell, err := newExportCell(val)
+			if err != nil {
+				return nil, fmt.Errorf("field[%s] %w", column.fieldName, err)
+			}
+			writeExportCell(xlsx, sheetName, colName+"2", cell, exportMappingText(tagKey, cell.text))
+		}
+	}
+
+	FrozenFirstRow(xlsx, sheetName)
+
+	if len(opt.Instructions) > 0 {
+		instructionsSheet := catalog.render(MsgTplInstructions, nil)
+		if _, err := xlsx.NewSheet(instructionsSheet); err != nil {
+			return nil, err
+		}
+		_ = xlsx.SetColWidth(instructionsSheet, "A", "A", 80)
+		for r, line := range opt.Instructions {
+			_ = xlsx.SetCellValue(instructionsSheet, "A"+strconv.Itoa(r+1), line)
+		}
+	}
+
+	return xlsx, nil
+}
+
+type templateColumn struct {
+	colIndex     int
+	name         string
+	fieldName    string
+	mappingField map[string]string
+}
+
+// templateColumns 按表头绑定的字段依次占用固定列之外的列，固定列写在 col(C) 或 index(2) 指定的位置
+func templateColumns(p *parser) []*templateColumn {
+	var columns []*templateColumn
+	occupied := make(map[int]bool)
+	for _, fc := range p.fixedColumns {
+		occupied[fc.colIndex] = true
+		columns = append(columns, &templateColumn{colIndex: fc.colIndex, name: fc.name, fieldName: fc.mappingField[nameTag], mappingField: fc.mappingField})
+	}
+	colIndex := 0
+	for _, fieldName := range p.fieldNames {
+		for occupied[colIndex] {
+			colIndex++
+		}
+		occupied[colIndex] = true
+		columns = append(columns, &templateColumn{colIndex: colIndex, name: p.fieldTitles[fieldName], fieldName: fieldName, mappingField: p.fieldMappingByName(fieldName)})
+	}
+	sort.SliceStable(columns, func(i, j int) bool { return columns[i].colIndex < columns[j].colIndex })
+	return columns
+}
+
+// templateExampleValue 按字段路径读取示例数据，路径中的指针为空时不写示例
+func templateExampleValue(val reflect.Value, fieldName string) (reflect.Value, bool) {
+	if !val.IsValid() {
+		return reflect.Value{}, false
+	}
+	for _, field := range strings.Split(fieldName, ".") {
+		for val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return reflect.Value{}, false
+			}
+			val = val.Elem()
+		}
+		if val.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		val = val.FieldByName(field)
+		if !val.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+	if val.Kind() == reflect.Ptr && val.IsNil() {
+		return reflect.Value{}, false
+	}
+	return val, true
+}
+
+func templateDateLayout(field reflect.StructField, dateFormat string) string {
+	if layouts, _ := splitDateTag(dateFormat); len(layouts) > 0 {
+		return layouts[0]
+	}
+	if isTimeField(field) {
+		return defaultTimeLayouts[0]
+	}
+	return ""
+}
+
+func templateDropList(tagOptions map[string]string) []string {
+	var options []string
+	if mapping := tagOptions[mappingTag]; mapping != "" {
+		for _, format := range strings.Split(mapping, ",") {
+			if n := strings.SplitN(format, ":", 2); len(n) == 2 {
+				options = append(options, n[0])
+			}
+		}
+		return options
+	}
+	if oneof := tagOptions[oneofTag]; oneof != "" {
+		options = strings.Split(oneof, "|")
+	}
+	return options
+}
+
+var excelDateReplacer = strings.NewReplacer("2006", "yyyy", "06", "yy", "01", "mm", "02", "dd", "15", "hh", "04", "mm", "05", "ss")
+
+// excelDateFormat 将 go 时间格式转换为 excel 数字格式
+func excelDateFormat(layout string) string {
+	return excelDateReplacer.Replace(layout)
+}
@@ -0,0 +1,87 @@
+package dgexcel
+
+import (
+	dgctx "github.com/darwinOrg/go-common/context"
+	"strings"
+	"testing"
+)
+
+type TemplateUser struct {
+	Name     string `excel:"name(姓名);required;unique;width(30)"`
+	Status   int    `excel:"name(状态);mapping(无效:0,有效:1)"`
+	Birthday string `excel:"name(生日);date(2006-01-02,2006年01月02日)"`
+	Remark   string
+}
+
+func TestGenerateImportTemplate(t *testing.T) {
+	xlsx, err := GenerateImportTemplate[TemplateUser](&TemplateOptions{
+		Example:      &TemplateUser{Name: "张三", Status: 1, Birthday: "2000-01-02"},
+		Instructions: []string{"姓名必填且不可重复"},
+		Rows:         100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := xlsx.GetRows(DefaultSheetName)
+	if err != nil || len(rows) != 2 || len(rows[0]) != 3 || rows[0][1] != "状态" || rows[1][1] != "有效" {
+		t.Fatalf("unexpected rows: %v, %v", rows, err)
+	}
+	if width, _ := xlsx.GetColWidth(DefaultSheetName, "A"); width != 30 {
+		t.Fatalf("unexpected width: %v", width)
+	}
+
+	dvs, err := xlsx.GetDataValidations(DefaultSheetName)
+	if err != nil || len(dvs) != 1 || dvs[0].Sqref != "B2:B101" || dvs[0].Formula1 != "\"无效,有效\"" {
+		t.Fatalf("unexpected data validations: %v, %v", dvs, err)
+	}
+
+	comments, err := xlsx.GetComments(DefaultSheetName)
+	if err != nil || len(comments) != 2 || comments[0].Cell != "A1" || comments[1].Cell != "C1" {
+		t.Fatalf("unexpected comments: %v, %v", comments, err)
+	}
+
+	if lines, _ := xlsx.GetRows("填写说明"); len(lines) != 1 {
+		t.Fatalf("unexpected instructions: %v", lines)
+	}
+
+	if got := excelDateFormat("2006-01-02 15:04:05"); got != "yyyy-mm-dd hh:mm:ss" {
+		t.Fatalf("unexpected date format: %s", got)
+	}
+}
+
+type TemplateAddress struct {
+	City string `excel:"name(城市)"`
+}
+
+type TemplateOrder struct {
+	No      string  `excel:"name(订单号);required;unique"`
+	Code    string  `excel:"name(编码);col(E)"`
+	Amount  float64 `excel:"name(金额(元))"`
+	Address TemplateAddress
+}
+
+func TestImportGeneratedTemplate(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	example := &TemplateOrder{No: "O001", Code: "X1", Amount: 12.5, Address: TemplateAddress{City: "上海"}}
+	xlsx, err := GenerateImportTemplate[TemplateOrder](&TemplateOptions{Example: example, Rows: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := xlsx.GetRows(DefaultSheetName)
+	if err != nil || len(rows) != 2 || strings.Join(rows[0], ",") != "订单号,金额(元),城市,,编码" {
+		t.Fatalf("unexpected rows: %v, %v", rows, err)
+	}
+	buf, err := xlsx.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orders, err := BindExcelBytes2Struct[TemplateOrder](ctx, buf.Bytes(), 1, 2, &BindOptions{StrictHeader: true, RequireAllColumns: true})
+	if err != nil {
+		t.Fatalf("bind template error: %v", err)
+	}
+	if len(orders) != 1 || *orders[0] != *example {
+		t.Fatalf("unexpected orders: %+v", orders)
+	}
+}
@@ -0,0 +1,116 @@
+package dgexcel
+
+import (
+	"strconv"
+	"strings"
+)
+
+// uniquePart 联合唯一键中一个字段在当前行的值
+type uniquePart struct {
+	key    string
+	value  string
+	header string
+	column string
+}
+
+// uniqueFormat 单列唯一性校验，按哈希索引查找之前出现的行；unique(groupName) 的字段先记录，整行绑定后再校验
+func (p *parser) uniqueFormat(meta CellMeta, col string, mappingField map[string]string) []*CellError {
+	errList := make([]*CellError, 0)
+	group, enabled := uniqueGroup(mappingField[uniqueTag])
+	if !enabled {
+		return errList
+	}
+	fieldName := mappingField[nameTag]
+	if group != "" {
+		p.rowUnique[fieldName] = &uniquePart{key: uniqueValue(col, mappingField), value: col, header: meta.Header, column: meta.Column}
+		return errList
+	}
+	if col == "" {
+		return errList
+	}
+	//流式读取时只与已读取的行比较
+	if conflictRow, ok := p.checkUnique(fieldName, uniqueValue(col, mappingField), meta.Row); !ok {
+		errList = append(errList, p.newCellErrorWithParams(CellErrorUnique, MsgUnique, meta.Header, col, map[string]string{"conflictRow": strconv.Itoa(conflictRow)}))
+	}
+	return errList
+}
+
+// uniqueGroupFormat 联合唯一键校验，组内字段都为空或已有单元格错误时跳过
+func (p *parser) uniqueGroupFormat(rowNum int, rowErrors []*CellError) []*CellError {
+	errList := make([]*CellError, 0)
+	failed := make(map[string]bool)
+	for _, ce := range rowErrors {
+		failed[ce.Field] = true
+	}
+	for _, group := range p.groupNames {
+		var keys, values, headers, fields []string
+		column := ""
+		empty, skip := true, false
+		for _, fieldName := range p.uniqueGroups[group] {
+			if failed[fieldName] {
+				skip = true
+				break
+			}
+			part, ok := p.rowUnique[fieldName]
+			if !ok {
+				part = &uniquePart{header: p.fieldTitles[fieldName]}
+			}
+			if part.key != "" {
+				empty = false
+			}
+			if column == "" {
+				column = part.column
+			}
+			keys = append(keys, part.key)
+			values = append(values, part.value)
+			headers = append(headers, part.header)
+			fields = append(fields, fieldName)
+		}
+		if skip || empty {
+			continue
+		}
+		conflictRow, ok := p.checkUnique("group:"+group, strings.Join(keys, "\x00"), rowNum)
+		if ok {
+			continue
+		}
+		header, value := strings.Join(headers, "+"), strings.Join(values, "+")
+		ce := p.newCellErrorWithParams(CellErrorUnique, MsgUnique, header, value, map[string]string{"conflictRow": strconv.Itoa(conflictRow)})
+		ce.Sheet, ce.Row, ce.Column, ce.Header, ce.Value, ce.Field = p.sheetName, rowNum, column, header, value, strings.Join(fields, "+")
+		errList = append(errList, ce)
+	}
+	return errList
+}
+
+// uniqueGroup 解析 unique 标签，unique 与 unique(true) 为单列唯一，unique(false) 或未设置时不校验，其余为联合唯一的组名
+func uniqueGroup(value string) (string, bool) {
+	switch value {
+	case "", "false":
+		return "", false
+	case "true":
+		return "", true
+	default:
+		return value, true
+	}
+}
+
+// checkUnique 键已存在时返回首次出现的行号
+func (p *parser) checkUnique(name, key string, rowNum int) (int, bool) {
+	index, ok := p.uniqueIndex[name]
+	if !ok {
+		index = make(map[string]int)
+		p.uniqueIndex[name] = index
+	}
+	if conflictRow, ok := index[key]; ok {
+		return conflictRow, false
+	}
+	index[key] = rowNum
+	return 0, true
+}
+
+// uniqueValue 标记 fold 时忽略大小写、全半角和空白
+func uniqueValue(col string, mappingField map[string]string) string {
+	if mappingField[foldTag] == "true" {
+		return NormalizeHeader(col)
+	}
+	return col
+}
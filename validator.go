@@ -0,0 +1,139 @@
+package dgexcel
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ValidatorFunc 自定义校验函数，返回的错误信息会作为校验失败原因
+type ValidatorFunc func(value string, field reflect.StructField) error
+
+var (
+	validators     = make(map[string]ValidatorFunc)
+	validatorsLock sync.RWMutex
+)
+
+// RegisterValidator 注册自定义校验，标签中通过 validate(name) 引用，多个用|分隔
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validatorsLock.Lock()
+	defer validatorsLock.Unlock()
+	validators[name] = fn
+}
+
+func getValidator(name string) (ValidatorFunc, bool) {
+	validatorsLock.RLock()
+	defer validatorsLock.RUnlock()
+	fn, ok := validators[name]
+	return fn, ok
+}
+
+func (p *parser) validate(mappingHeader, col string, mappingField map[string]string) ([]*CellError, error) {
+	errList := make([]*CellError, 0)
+	if col == "" {
+		if mappingField[requiredTag] == "true" {
+			errList = append(errList, p.newCellError(CellErrorRequired, MsgRequired, mappingHeader, col))
+		}
+		return errList, nil
+	}
+
+	if rule, ok := mappingField[minTag]; ok {
+		if !compareNumber(col, rule, func(v, limit float64) bool { return v >= limit }) {
+			errList = append(errList, p.newRuleError(MsgMin, mappingHeader, col, map[string]string{"min": rule}))
+		}
+	}
+	if rule, ok := mappingField[maxTag]; ok {
+		if !compareNumber(col, rule, func(v, limit float64) bool { return v <= limit }) {
+			errList = append(errList, p.newRuleError(MsgMax, mappingHeader, col, map[string]string{"max": rule}))
+		}
+	}
+	if rule, ok := mappingField[lenTag]; ok {
+		minLen, maxLen := parseLenRule(rule)
+		length := utf8.RuneCountInString(col)
+		if length < minLen || length > maxLen {
+			errList = append(errList, p.newRuleError(MsgLen, mappingHeader, col, map[string]string{"min": strconv.Itoa(minLen), "max": strconv.Itoa(maxLen)}))
+		}
+	}
+	if rule, ok := mappingField[regexTag]; ok {
+		reg, err := p.compileRegex(rule)
+		if err != nil {
+			return nil, err
+		}
+		if !reg.MatchString(col) {
+			errList = append(errList, p.newRuleError(MsgRegex, mappingHeader, col, nil))
+		}
+	}
+	if mappingField[emailTag] == "true" && !emailRegex.MatchString(col) {
+		errList = append(errList, p.newRuleError(MsgEmail, mappingHeader, col, nil))
+	}
+	if rule, ok := mappingField[oneofTag]; ok {
+		options := strings.Split(rule, "|")
+		matched := false
+		for _, option := range options {
+			if strings.TrimSpace(option) == col {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			errList = append(errList, p.newRuleError(MsgOneOf, mappingHeader, col, map[string]string{"options": strings.Join(options, "/")}))
+		}
+	}
+	if rule, ok := mappingField[validateTag]; ok {
+		for _, name := range strings.Split(rule, "|") {
+			name = strings.TrimSpace(name)
+			fn, ok := getValidator(name)
+			if !ok {
+				return nil, fmt.Errorf("excel column[%s] validator[%s] not registered", mappingHeader, name)
+			}
+			if err := fn(col, p.structFields[mappingField[nameTag]]); err != nil {
+				errList = append(errList, p.newRuleError(MsgValidate, mappingHeader, col, map[string]string{"reason": err.Error()}))
+			}
+		}
+	}
+	return errList, nil
+}
+
+func (p *parser) newRuleError(key, mappingHeader, col string, params map[string]string) *CellError {
+	return p.newCellErrorWithParams(CellErrorValidate, key, mappingHeader, col, params)
+}
+
+func (p *parser) compileRegex(rule string) (*regexp.Regexp, error) {
+	if reg, ok := p.regexCache[rule]; ok {
+		return reg, nil
+	}
+	reg, err := regexp.Compile(rule)
+	if err != nil {
+		return nil, err
+	}
+	p.regexCache[rule] = reg
+	return reg, nil
+}
+
+func compareNumber(col, rule string, cmp func(v, limit float64) bool) bool {
+	v, err := strconv.ParseFloat(col, 64)
+	if err != nil {
+		return false
+	}
+	limit, err := strconv.ParseFloat(strings.TrimSpace(rule), 64)
+	if err != nil {
+		return false
+	}
+	return cmp(v, limit)
+}
+
+// parseLenRule 解析 len(max) 或 len(min,max)
+func parseLenRule(rule string) (int, int) {
+	parts := strings.SplitN(rule, ",", 2)
+	if len(parts) == 1 {
+		maxLen, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
+		return 0, maxLen
+	}
+	minLen, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
+	maxLen, _ := strconv.Atoi(strings.TrimSpace(parts[1]))
+	return minLen, maxLen
+}
@@ -0,0 +1,127 @@
+package dgexcel
+
+import (
+	"errors"
+	dgctx "github.com/darwinOrg/go-common/context"
+	"reflect"
+	"testing"
+)
+
+type Member struct {
+	Name   string `excel:"name(姓名);required;len(1,4)"`
+	Age    int    `excel:"name(年龄);min(0);max(150)"`
+	Phone  string `excel:"name(手机号);regex(^1\\d{10}$)"`
+	Email  string `excel:"name(邮箱);email"`
+	Level  string `excel:"name(等级);oneof(A|B|C)"`
+	Remark string `excel:"name(备注)"`
+}
+
+func TestValidateTags(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "姓名"}, {Name: "年龄"}, {Name: "手机号"}, {Name: "邮箱"}, {Name: "等级"}, {Name: "备注"}},
+		Datas: [][]any{
+			{"张三", 20, "13800000000", "zs@example.com", "A", "ok"},
+			{"", 200, "12345", "zs#example.com", "D"},
+		},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = BindExcelBytes2Struct[Member](ctx, buf.Bytes(), 1, 2)
+	var ie *ImportError
+	if !errors.As(err, &ie) {
+		t.Fatalf("expected import error, got: %v", err)
+	}
+	if len(ie.CellErrors) != 5 {
+		t.Fatalf("unexpected errors: \n%v", ie)
+	}
+	if ie.CellErrors[0].Code != CellErrorRequired {
+		t.Fatalf("unexpected first error: %+v", ie.CellErrors[0])
+	}
+}
+
+type Company struct {
+	Name     string `excel:"name(企业名称)"`
+	CreditNo string `excel:"name(统一社会信用代码);validate(creditNo)"`
+}
+
+func TestRegisterValidator(t *testing.T) {
+	RegisterValidator("creditNo", func(value string, field reflect.StructField) error {
+		if len(value) != 18 {
+			return errors.New("长度须为18位")
+		}
+		return nil
+	})
+
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "企业名称"}, {Name: "统一社会信用代码"}},
+		Datas:   [][]any{{"甲公司", "91350100M000100Y43"}, {"乙公司", "123"}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = BindExcelBytes2Struct[Company](ctx, buf.Bytes(), 1, 2)
+	if err == nil || err.Error() != "第3行：统一社会信用代码[123]校验失败：长度须为18位" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+type Inventory struct {
+	Sku       string `excel:"name(SKU);unique;fold"`
+	Warehouse string `excel:"name(仓库);unique(location)"`
+	Shelf     string `excel:"name(货架);unique(location)"`
+}
+
+func TestUniqueConstraints(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "SKU"}, {Name: "仓库"}, {Name: "货架"}},
+		Datas: [][]any{
+			{"ab01", "上海", "A1"},
+			{"AB 01", "上海", "A2"},
+			{"ab-02", "北京", "A1"},
+			{"ab-03", "上海", "A1"},
+		},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = BindExcelBytes2Struct[Inventory](ctx, buf.Bytes(), 1, 2)
+	var ie *ImportError
+	if !errors.As(err, &ie) || len(ie.CellErrors) != 2 {
+		t.Fatalf("unexpected errors: %v", err)
+	}
+	skuErr, groupErr := ie.CellErrors[0], ie.CellErrors[1]
+	if skuErr.Row != 3 || skuErr.Column != "A" || skuErr.Message != "SKU[AB 01]不可重复，与第2行重复" {
+		t.Fatalf("unexpected sku error: %+v", skuErr)
+	}
+	if groupErr.Row != 5 || groupErr.Column != "B" || groupErr.Message != "仓库+货架[上海+A1]不可重复，与第2行重复" {
+		t.Fatalf("unexpected group error: %+v", groupErr)
+	}
+}
+
+type Catalog struct {
+	Name string `excel:"name(姓名);unique(false)"`
+	Code string `excel:"name(编码);unique(false)"`
+}
+
+func TestUniqueDisabled(t *testing.T) {
+	ctx := &dgctx.DgContext{TraceId: "123"}
+	buf, err := ExportExcelSheets([]*ExcelSheet{{
+		Headers: []*ExcelHeader{{Name: "姓名"}, {Name: "编码"}},
+		Datas:   [][]any{{"a", "1"}, {"a", "1"}},
+	}}).WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	catalogs, err := BindExcelBytes2Struct[Catalog](ctx, buf.Bytes(), 1, 2)
+	if err != nil || len(catalogs) != 2 {
+		t.Fatalf("unexpected result: %v, %v", catalogs, err)
+	}
+}